@@ -0,0 +1,38 @@
+package access
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour)
+	signer := NewSigner("secret")
+	token := signer.Sign(expires)
+
+	got, err := signer.Verify(token, now)
+	if err != nil || !got.Equal(expires) {
+		t.Errorf("Verify() = %v, %v, want %v", got, err, expires)
+	}
+	if _, err := signer.Verify(token, expires); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify() at the expiry error = %v, want ErrExpired", err)
+	}
+	if _, err := NewSigner("other").Verify(token, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Verify() with another key error = %v, want ErrInvalid", err)
+	}
+
+	// Moving the expiry invalidates the signature
+	payload, _, _ := strings.Cut(signer.Sign(expires.Add(time.Hour)), ".")
+	_, mac, _ := strings.Cut(token, ".")
+	if _, err := signer.Verify(payload+"."+mac, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Verify() of a tampered token error = %v, want ErrInvalid", err)
+	}
+	for _, bad := range []string{"", "abc", "."} {
+		if _, err := signer.Verify(bad, now); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Verify(%q) error = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/access"
+	"github.com/fairytale5571/cv_onopchenko/analytics"
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+const (
+	// AdminTokenEnv is read when -admin-token is not given
+	AdminTokenEnv = "CV_ADMIN_TOKEN"
+
+	// Periods of the statistics summary in days. The longest one and the
+	// period it is compared to fit in analytics.RetentionDays.
+	DefaultStatsDays = 30
+	MaxStatsDays     = analytics.RetentionDays / 2
+
+	// AdminCookie keeps the session of a browser that logged in with the
+	// token, for AdminSessionTTL
+	AdminCookie     = "cv_admin"
+	AdminSessionTTL = 12 * time.Hour
+)
+
+// adminSessions signs the session cookies with the admin token, so that
+// changing the token logs every browser out
+func adminSessions(token string) *access.Signer {
+	return access.NewSigner("admin-session:" + token)
+}
+
+// authorized reports whether r carries the admin token as a bearer token or
+// the cookie of a login session. The token is never read from the URL, which
+// ends up in logs, browser history and Referer headers.
+func authorized(token string, r *http.Request) bool {
+	if token == "" {
+		return false
+	}
+
+	if given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+	}
+
+	cookie, err := r.Cookie(AdminCookie)
+	if err != nil {
+		return false
+	}
+	_, err = adminSessions(token).Verify(cookie.Value, time.Now())
+	return err == nil
+}
+
+// noStore keeps the admin pages out of caches and search engines
+func noStore(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+}
+
+// requireToken lets through authorized requests and answers the others
+// with 401, for the API
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(token, r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cv admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		noStore(w)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireLogin is requireToken for the editor pages: browsers without a
+// session are sent to the login page. Changes must come from the site
+// itself; the SameSite cookie already keeps them so in current browsers.
+func requireLogin(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(token, r) {
+			if r.Method != http.MethodGet {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, "/admin/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+			return
+		}
+		if origin := r.Header.Get("Origin"); r.Method != http.MethodGet && origin != "" {
+			if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+
+		noStore(w)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveLogin shows the login form and starts a session for the right token
+func serveLogin(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		noStore(w)
+		next := adminNext(r.FormValue("next"))
+		if r.Method != http.MethodPost {
+			render(w, r, http.StatusOK, components.AdminLogin(next, false))
+			return
+		}
+
+		given := r.PostFormValue("token")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			slog.Warn("failed admin login", "remote", r.RemoteAddr)
+			render(w, r, http.StatusUnauthorized, components.AdminLogin(next, true))
+			return
+		}
+
+		expires := time.Now().Add(AdminSessionTTL)
+		http.SetCookie(w, &http.Cookie{
+			Name:     AdminCookie,
+			Value:    adminSessions(token).Sign(expires),
+			Path:     "/admin",
+			Expires:  expires,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	}
+}
+
+// serveLogout ends the session of the browser
+func serveLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     AdminCookie,
+		Path:     "/admin",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+}
+
+// adminNext is the page to return to after logging in; only admin pages
+// are accepted, so the login cannot redirect elsewhere
+func adminNext(next string) string {
+	if next == "/admin" || strings.HasPrefix(next, "/admin/") {
+		return next
+	}
+	return "/admin"
+}
+
+// statsDays reads the days query parameter of the statistics endpoints
+func statsDays(r *http.Request) int {
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil || days < 1 {
+		return DefaultStatsDays
+	}
+	return min(days, MaxStatsDays)
+}
+
+// serveStats renders the statistics page
+func serveStats(resumeData *components.ResumeData, store *analytics.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		days := statsDays(r)
+		summary := store.Summarize(days, time.Now())
+
+		page := components.Stats(resumeData.Personal.Name, summary, days)
+		err := page.Render(r.Context(), w)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// serveStatsJSON serves the statistics summary as JSON
+func serveStatsJSON(store *analytics.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(store.Summarize(statsDays(r), time.Now()))
+		if err != nil {
+			http.Error(w, "Failed to encode statistics: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// trackVisits records an analytics event of kind for every successful request
+func (s *site) trackVisits(kind string, next http.Handler) http.Handler {
+	if s.tracker == nil {
+		return next
+	}
+
+	variant := variantLabel(s.resumeData)
+	locale := s.resumeData.Page.Locale
+	if locale == "" {
+		locale = components.DefaultLocale
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status >= http.StatusBadRequest {
+			return
+		}
+		if err := s.tracker.Track(r, kind, variant, locale); err != nil {
+			slog.Error("error tracking visit", "kind", kind, "error", err)
+		}
+	})
+}
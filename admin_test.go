@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/analytics"
+)
+
+func TestAdminStats(t *testing.T) {
+	store, err := analytics.Open(filepath.Join(t.TempDir(), "analytics.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	s := testSite(nil)
+	s.adminToken = "secret"
+	s.enableAnalytics(store, analytics.NewTracker(store))
+	routes := s.routes()
+
+	for _, path := range []string{"/", "/", "/favicon.ico"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("User-Agent", "Mozilla/5.0")
+		r.Header.Set("Referer", "https://github.com/someone")
+		routes.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	if rec := get(t, routes, "/admin/stats.json"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("/admin/stats.json without a token = %d, want 401", rec.Code)
+	}
+	if rec := get(t, routes, "/admin/stats.json?token=secret"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("/admin/stats.json with the token in the URL = %d, want 401", rec.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/admin/stats.json?days=7", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	var summary analytics.Summary
+	if err := json.Unmarshal(rec.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("/admin/stats.json = %d %q: %v", rec.Code, rec.Body.String(), err)
+	}
+	if summary.Views != 2 || len(summary.Daily) != 7 || summary.Referrers[0].Name != "github.com" || summary.Variants[0].Name != "backend" {
+		t.Errorf("summary = %+v, want 2 views over 7 days from github.com of backend", summary)
+	}
+
+	if rec := get(t, routes, "/admin/stats?token=secret"); rec.Code != http.StatusSeeOther {
+		t.Errorf("/admin/stats with the token in the URL = %d, want a redirect to the login", rec.Code)
+	}
+	r = httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	page := httptest.NewRecorder()
+	routes.ServeHTTP(page, r)
+	if page.Code != http.StatusOK || !strings.Contains(page.Body.String(), "github.com") {
+		t.Errorf("/admin/stats = %d, want the page listing the referrer", page.Code)
+	}
+	if strings.Contains(page.Body.String(), "secret") {
+		t.Error("/admin/stats puts the admin token into the page")
+	}
+}
+
+func TestAdminStatsDisabledWithoutToken(t *testing.T) {
+	store, err := analytics.Open(filepath.Join(t.TempDir(), "analytics.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	s := testSite(nil)
+	s.enableAnalytics(store, analytics.NewTracker(store))
+	rec := get(t, s.routes(), "/admin/stats.json?token=")
+	if strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("/admin/stats.json is served without an admin token")
+	}
+}
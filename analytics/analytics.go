@@ -0,0 +1,379 @@
+// Package analytics counts page views and downloads without third-party
+// trackers. Visitors are identified only by a hash of their IP address and
+// user agent salted with a random value that changes every day and is never
+// written to disk, so hashes cannot be linked across days or reversed.
+package analytics
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event kinds
+const (
+	KindView     = "view"
+	KindDownload = "download"
+)
+
+// DayLayout is the format of Event.Day
+const DayLayout = "2006-01-02"
+
+// DirectReferrer labels visits without a referrer from another site
+const DirectReferrer = "(direct)"
+
+// visitorHashLength is the number of hex characters kept from the hash
+const visitorHashLength = 16
+
+// RetentionDays is how long events are kept: the longest summary, a year,
+// and the year it is compared to. Older events are dropped from memory and
+// from the file.
+const RetentionDays = 2 * 365
+
+// Event is a single recorded view or download
+type Event struct {
+	Day      string `json:"day"`
+	Kind     string `json:"kind"`
+	Referrer string `json:"referrer"`
+	Variant  string `json:"variant"`
+	Locale   string `json:"locale"`
+	Visitor  string `json:"visitor"`
+}
+
+// Store keeps the events of the last RetentionDays in a JSON Lines file and
+// in memory
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	events []Event
+	// prunedDay is the last day old events were dropped on
+	prunedDay string
+}
+
+// Open loads the events recorded in path, drops the expired ones and
+// appends new events to it
+func Open(path string) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening analytics store: %w", err)
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error parsing analytics store %s line %d: %w", path, line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error reading analytics store: %w", err)
+	}
+
+	s := &Store{path: path, file: file, events: events}
+	if err := s.Prune(time.Now()); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Record appends an event to the store. The first event of a day drops the
+// events that expired.
+func (s *Store) Record(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.Day > s.prunedDay {
+		if day, err := time.Parse(DayLayout, event.Day); err == nil {
+			if err := s.prune(day); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing analytics event: %w", err)
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Prune drops the events older than RetentionDays before the day of now and
+// rewrites the file without them
+func (s *Store) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.prune(now)
+}
+
+// prune is Prune for callers holding mu
+func (s *Store) prune(now time.Time) error {
+	today := now.UTC().Truncate(24 * time.Hour)
+	s.prunedDay = today.Format(DayLayout)
+	// Days are ISO dates, so they compare as strings
+	oldest := today.AddDate(0, 0, -RetentionDays).Format(DayLayout)
+	kept := slices.DeleteFunc(slices.Clone(s.events), func(event Event) bool { return event.Day < oldest })
+	if len(kept) == len(s.events) {
+		return nil
+	}
+
+	// Write the kept events next to the file and swap them in, so that a
+	// crash leaves either the old or the new file
+	tmp := s.path + ".tmp"
+	var b []byte
+	for _, event := range kept {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		b = append(append(b, line...), '\n')
+	}
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("error pruning analytics store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("error pruning analytics store: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("error reopening analytics store: %w", err)
+	}
+	s.file.Close()
+	s.file, s.events = file, kept
+	return nil
+}
+
+// Close closes the store file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Count is the number of events with a given referrer, variant or locale
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DaySummary holds the totals of a single day
+type DaySummary struct {
+	Day       string `json:"day"`
+	Views     int    `json:"views"`
+	Downloads int    `json:"downloads"`
+	Visitors  int    `json:"visitors"`
+}
+
+// Summary describes the events of a period and compares it to the period
+// of the same length before it
+type Summary struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Views     int    `json:"views"`
+	Downloads int    `json:"downloads"`
+	// Visitors adds up the daily unique visitors; the daily salt makes the
+	// same person on two days two visitors
+	Visitors          int          `json:"visitors"`
+	PreviousViews     int          `json:"previousViews"`
+	PreviousDownloads int          `json:"previousDownloads"`
+	Daily             []DaySummary `json:"daily"`
+	Referrers         []Count      `json:"referrers"`
+	Variants          []Count      `json:"variants"`
+	Locales           []Count      `json:"locales"`
+}
+
+// Summarize summarizes the last days days up to and including the day of now
+func (s *Store) Summarize(days int, now time.Time) Summary {
+	to := now.UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, 1-days)
+	previousFrom := from.AddDate(0, 0, -days)
+
+	summary := Summary{From: from.Format(DayLayout), To: to.Format(DayLayout)}
+	previousStart := previousFrom.Format(DayLayout)
+
+	daily := map[string]*DaySummary{}
+	visitors := map[string]map[string]bool{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := d.Format(DayLayout)
+		daily[day] = &DaySummary{Day: day}
+		visitors[day] = map[string]bool{}
+	}
+	referrers, variants, locales := map[string]int{}, map[string]int{}, map[string]int{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.events {
+		// Days are ISO dates, so they compare as strings
+		if event.Day >= previousStart && event.Day < summary.From {
+			switch event.Kind {
+			case KindView:
+				summary.PreviousViews++
+			case KindDownload:
+				summary.PreviousDownloads++
+			}
+			continue
+		}
+
+		day, ok := daily[event.Day]
+		if !ok {
+			continue
+		}
+		switch event.Kind {
+		case KindView:
+			summary.Views++
+			day.Views++
+			referrers[event.Referrer]++
+		case KindDownload:
+			summary.Downloads++
+			day.Downloads++
+		}
+		variants[event.Variant]++
+		locales[event.Locale]++
+		if event.Visitor != "" {
+			visitors[event.Day][event.Visitor] = true
+		}
+	}
+
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := daily[d.Format(DayLayout)]
+		day.Visitors = len(visitors[day.Day])
+		summary.Visitors += day.Visitors
+		summary.Daily = append(summary.Daily, *day)
+	}
+	summary.Referrers = sortCounts(referrers)
+	summary.Variants = sortCounts(variants)
+	summary.Locales = sortCounts(locales)
+	return summary
+}
+
+// sortCounts orders counts from the most frequent, then by name
+func sortCounts(counts map[string]int) []Count {
+	list := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, Count{Name: name, Count: count})
+	}
+	slices.SortFunc(list, func(a, b Count) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
+
+// Tracker turns requests into anonymous events
+type Tracker struct {
+	store *Store
+	now   func() time.Time
+
+	mu      sync.Mutex
+	salt    []byte
+	saltDay string
+}
+
+// NewTracker records events into store
+func NewTracker(store *Store) *Tracker {
+	return &Tracker{store: store, now: time.Now}
+}
+
+// Track records a view or download of the given variant and locale. Requests
+// with Do Not Track or Global Privacy Control set and obvious bots are skipped.
+func (t *Tracker) Track(r *http.Request, kind, variant, locale string) error {
+	if r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1" || isBot(r.UserAgent()) {
+		return nil
+	}
+
+	now := t.now().UTC()
+	day := now.Format(DayLayout)
+	visitor, err := t.visitor(day, clientIP(r), r.UserAgent())
+	if err != nil {
+		return err
+	}
+
+	return t.store.Record(Event{
+		Day:      day,
+		Kind:     kind,
+		Referrer: ReferrerDomain(r.Referer(), r.Host),
+		Variant:  variant,
+		Locale:   locale,
+		Visitor:  visitor,
+	})
+}
+
+// visitor hashes the IP address and user agent with the salt of the day
+func (t *Tracker) visitor(day, ip, userAgent string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.saltDay != day {
+		salt := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return "", fmt.Errorf("error generating analytics salt: %w", err)
+		}
+		t.salt, t.saltDay = salt, day
+	}
+
+	h := sha256.New()
+	h.Write(t.salt)
+	h.Write([]byte(ip))
+	h.Write([]byte{0})
+	h.Write([]byte(userAgent))
+	return hex.EncodeToString(h.Sum(nil))[:visitorHashLength], nil
+}
+
+// ReferrerDomain reduces a Referer header to the domain of the referring
+// site. Links from the site itself and missing referrers count as direct.
+func ReferrerDomain(referer, host string) string {
+	u, err := url.Parse(referer)
+	if referer == "" || err != nil || u.Hostname() == "" {
+		return DirectReferrer
+	}
+
+	domain := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if ownHost, _, err := net.SplitHostPort(host); err == nil {
+		host = ownHost
+	}
+	if domain == strings.TrimPrefix(strings.ToLower(host), "www.") {
+		return DirectReferrer
+	}
+	return domain
+}
+
+// clientIP is the address of the visitor. X-Forwarded-For is not trusted:
+// run behind a proxy that overwrites RemoteAddr if it matters.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func isBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	return ua == "" || strings.Contains(ua, "bot") || strings.Contains(ua, "crawler") || strings.Contains(ua, "spider")
+}
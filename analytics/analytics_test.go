@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openStore(t *testing.T, path string) *Store {
+	t.Helper()
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func visit(referer, userAgent string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "http://cv.example.com/", nil)
+	r.Header.Set("Referer", referer)
+	r.Header.Set("User-Agent", userAgent)
+	return r
+}
+
+func TestStoreKeepsEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+	store := openStore(t, path)
+	event := Event{Day: time.Now().UTC().Format(DayLayout), Kind: KindView, Referrer: "linkedin.com", Variant: "default", Locale: "en", Visitor: "abc"}
+	if err := store.Record(event); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	store.Close()
+
+	reopened := openStore(t, path)
+	if len(reopened.events) != 1 || reopened.events[0] != event {
+		t.Errorf("reopened store has %+v, want [%+v]", reopened.events, event)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+	store := openStore(t, path)
+	now := time.Now().UTC()
+	old := Event{Day: now.AddDate(0, 0, -RetentionDays-1).Format(DayLayout), Kind: KindView}
+	kept := Event{Day: now.AddDate(0, 0, -RetentionDays).Format(DayLayout), Kind: KindView}
+	for _, event := range []Event{old, kept} {
+		if err := store.Record(event); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if len(store.events) != 2 {
+		t.Fatalf("store has %d events before pruning, want 2", len(store.events))
+	}
+
+	if err := store.Prune(now); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(store.events) != 1 || store.events[0] != kept {
+		t.Errorf("pruned store has %+v, want [%+v]", store.events, kept)
+	}
+
+	// New events go to the rewritten file
+	today := Event{Day: now.Format(DayLayout), Kind: KindDownload}
+	if err := store.Record(today); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	store.Close()
+	reopened := openStore(t, path)
+	if len(reopened.events) != 2 || reopened.events[0] != kept || reopened.events[1] != today {
+		t.Errorf("reopened store has %+v, want [%+v %+v]", reopened.events, kept, today)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	store := openStore(t, filepath.Join(t.TempDir(), "analytics.jsonl"))
+	for _, event := range []Event{
+		{Day: "2024-04-24", Kind: KindView, Referrer: DirectReferrer, Variant: "default", Locale: "en", Visitor: "a"},
+		{Day: "2024-04-30", Kind: KindView, Referrer: "linkedin.com", Variant: "backend", Locale: "en", Visitor: "a"},
+		{Day: "2024-05-01", Kind: KindView, Referrer: "linkedin.com", Variant: "backend", Locale: "ru", Visitor: "b"},
+		{Day: "2024-05-01", Kind: KindView, Referrer: DirectReferrer, Variant: "default", Locale: "en", Visitor: "b"},
+		{Day: "2024-05-01", Kind: KindDownload, Referrer: DirectReferrer, Variant: "backend", Locale: "en", Visitor: "b"},
+		{Day: "2024-01-01", Kind: KindView, Referrer: "old.example", Variant: "default", Locale: "en", Visitor: "c"},
+	} {
+		if err := store.Record(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary := store.Summarize(2, time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC))
+	if summary.From != "2024-04-30" || summary.To != "2024-05-01" {
+		t.Errorf("period = %s - %s, want 2024-04-30 - 2024-05-01", summary.From, summary.To)
+	}
+	if summary.Views != 3 || summary.Downloads != 1 || summary.Visitors != 2 {
+		t.Errorf("totals = %d views, %d downloads, %d visitors, want 3, 1, 2", summary.Views, summary.Downloads, summary.Visitors)
+	}
+	if summary.PreviousViews != 0 {
+		t.Errorf("PreviousViews = %d, want 0", summary.PreviousViews)
+	}
+	if len(summary.Daily) != 2 || summary.Daily[1] != (DaySummary{Day: "2024-05-01", Views: 2, Downloads: 1, Visitors: 1}) {
+		t.Errorf("Daily = %+v", summary.Daily)
+	}
+	if want := (Count{Name: "linkedin.com", Count: 2}); len(summary.Referrers) != 2 || summary.Referrers[0] != want {
+		t.Errorf("Referrers = %+v, want %+v first", summary.Referrers, want)
+	}
+	if want := (Count{Name: "backend", Count: 3}); summary.Variants[0] != want {
+		t.Errorf("Variants = %+v, want %+v first", summary.Variants, want)
+	}
+
+	week := store.Summarize(7, time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC))
+	if week.Views != 0 || week.PreviousViews != 3 || week.PreviousDownloads != 1 {
+		t.Errorf("next week = %d views after %d views and %d downloads, want 0 after 3 and 1", week.Views, week.PreviousViews, week.PreviousDownloads)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	store := openStore(t, filepath.Join(t.TempDir(), "analytics.jsonl"))
+	tracker := NewTracker(store)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	if err := tracker.Track(visit("https://www.linkedin.com/in/someone", "Mozilla/5.0"), KindView, "backend", "en"); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if err := tracker.Track(visit("", "Mozilla/5.0"), KindDownload, "backend", "en"); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	dnt := visit("", "Mozilla/5.0")
+	dnt.Header.Set("DNT", "1")
+	gpc := visit("", "Mozilla/5.0")
+	gpc.Header.Set("Sec-GPC", "1")
+	for _, r := range []*http.Request{dnt, gpc, visit("", "Googlebot/2.1"), visit("", "")} {
+		if err := tracker.Track(r, KindView, "backend", "en"); err != nil {
+			t.Fatalf("Track() error = %v", err)
+		}
+	}
+
+	if len(store.events) != 2 {
+		t.Fatalf("recorded %d events, want 2: %+v", len(store.events), store.events)
+	}
+	view, download := store.events[0], store.events[1]
+	if view.Day != "2024-05-01" || view.Referrer != "linkedin.com" || view.Variant != "backend" || view.Locale != "en" {
+		t.Errorf("view = %+v", view)
+	}
+	if len(view.Visitor) != visitorHashLength || view.Visitor != download.Visitor {
+		t.Errorf("visitors = %q and %q, want the same %d character hash", view.Visitor, download.Visitor, visitorHashLength)
+	}
+
+	// A new day gets a new salt, so the same visitor gets a different hash
+	now = now.Add(24 * time.Hour)
+	if err := tracker.Track(visit("", "Mozilla/5.0"), KindView, "backend", "en"); err != nil {
+		t.Fatal(err)
+	}
+	if next := store.events[2].Visitor; next == view.Visitor {
+		t.Errorf("visitor hash %q did not change with the day", next)
+	}
+}
+
+func TestReferrerDomain(t *testing.T) {
+	for _, tc := range []struct {
+		referer, host, want string
+	}{
+		{"", "cv.example.com", DirectReferrer},
+		{"not a url", "cv.example.com", DirectReferrer},
+		{"https://www.LinkedIn.com/feed?x=1", "cv.example.com", "linkedin.com"},
+		{"https://cv.example.com/", "cv.example.com:8081", DirectReferrer},
+		{"https://github.com/someone", "localhost:8081", "github.com"},
+	} {
+		if got := ReferrerDomain(tc.referer, tc.host); got != tc.want {
+			t.Errorf("ReferrerDomain(%q, %q) = %q, want %q", tc.referer, tc.host, got, tc.want)
+		}
+	}
+}
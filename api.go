@@ -0,0 +1,188 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+)
+
+// APIPrefix is the path of the read API; a change that breaks clients gets
+// a new version
+const APIPrefix = "/api/v1"
+
+// openAPIDocument describes the read API in the OpenAPI 3.1 format
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// resumeAPI serves the public resume data as JSON for other tools
+type resumeAPI struct {
+	// locales holds the full resume of every translation, without the
+	// private fields
+	locales map[string]*components.ResumeData
+	// locale and variant are used when a request does not choose one, so
+	// that the API matches the page
+	locale  string
+	variant string
+	// origins may call the API from a browser; "*" allows every site
+	origins []string
+}
+
+// loadAPI loads every translation of the config for the API
+func loadAPI(opts options, origins []string) (*resumeAPI, error) {
+	locales, err := resume.Locales(opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	api := &resumeAPI{
+		locales: map[string]*components.ResumeData{},
+		locale:  opts.locale,
+		variant: opts.variant,
+		origins: origins,
+	}
+	if api.locale == "" {
+		api.locale = components.DefaultLocale
+	}
+	for _, locale := range locales {
+		data, err := resume.Load(opts.configPath, locale)
+		if err != nil {
+			return nil, err
+		}
+		public := resume.Redact(*data)
+		api.locales[locale] = &public
+	}
+	return api, nil
+}
+
+// enableAPI serves the read API under APIPrefix
+func (s *site) enableAPI(api *resumeAPI) {
+	s.api = api
+}
+
+// routes registers the API on mux
+func (a *resumeAPI) routes(mux *http.ServeMux) {
+	mux.Handle("GET "+APIPrefix+"/resume", a.cors(http.HandlerFunc(a.serveResume)))
+	mux.Handle("GET "+APIPrefix+"/experience", a.cors(http.HandlerFunc(a.serveExperience)))
+	mux.Handle("GET "+APIPrefix+"/skills", a.cors(http.HandlerFunc(a.serveSkills)))
+	mux.Handle("GET "+APIPrefix+"/openapi.json", a.cors(http.HandlerFunc(serveOpenAPI)))
+	mux.Handle("OPTIONS "+APIPrefix+"/", a.cors(http.NotFoundHandler()))
+}
+
+// apiError is the body of failed API requests
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// resume returns the resume in the locale and variant chosen by the query.
+// On failure it has already answered the request.
+func (a *resumeAPI) resume(w http.ResponseWriter, r *http.Request) (components.ResumeData, bool) {
+	query := r.URL.Query()
+	locale := a.locale
+	if query.Has("locale") {
+		locale = query.Get("locale")
+	}
+	variant := a.variant
+	if query.Has("variant") {
+		variant = query.Get("variant")
+	}
+
+	data, ok := a.locales[locale]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, apiError{fmt.Sprintf("unknown locale %q", locale)})
+		return components.ResumeData{}, false
+	}
+	result, err := resume.ApplyVariant(*data, variant)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+		return components.ResumeData{}, false
+	}
+
+	w.Header().Set("Content-Language", locale)
+	return result, true
+}
+
+func (a *resumeAPI) serveResume(w http.ResponseWriter, r *http.Request) {
+	if data, ok := a.resume(w, r); ok {
+		writeJSON(w, http.StatusOK, data)
+	}
+}
+
+func (a *resumeAPI) serveExperience(w http.ResponseWriter, r *http.Request) {
+	if data, ok := a.resume(w, r); ok {
+		writeJSON(w, http.StatusOK, orEmpty(data.Experience))
+	}
+}
+
+// serveSkills serves the skill categories, or the one named by the
+// category parameter in any case
+func (a *resumeAPI) serveSkills(w http.ResponseWriter, r *http.Request) {
+	data, ok := a.resume(w, r)
+	if !ok {
+		return
+	}
+
+	skills := data.Skills
+	if category := r.URL.Query().Get("category"); category != "" {
+		skills = slices.DeleteFunc(slices.Clone(skills), func(skill components.SkillCategory) bool {
+			return !strings.EqualFold(skill.Category, category)
+		})
+	}
+	writeJSON(w, http.StatusOK, orEmpty(skills))
+}
+
+// orEmpty makes empty lists encode as [] rather than null
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(openAPIDocument)
+}
+
+// cors lets the allowed origins call the API from a browser and answers
+// their preflight requests
+func (a *resumeAPI) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		switch {
+		case slices.Contains(a.origins, "*"):
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case len(a.origins) > 0:
+			// The answer depends on the origin, so caches keep one per origin
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && slices.Contains(a.origins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
+
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiOrigins parses the -api-origins flag
+func apiOrigins(value string) []string {
+	var origins []string
+	for _, origin := range strings.Split(value, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	return origins
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+const apiConfig = `personal:
+  name: "Jane Doe"
+  title: "Developer"
+  phone: "+1 555 0100"
+skills:
+  - category: "Backend"
+    items:
+      - name: "Go"
+  - category: "Game design"
+    items:
+      - name: "Unity"
+    variants: [gamedev]
+experience:
+  - company: "ACME"
+    position: "Go Developer"
+    startDate: "2022"
+    endDate: "Present"
+variants:
+  - name: gamedev
+private:
+  - personal.phone
+`
+
+// apiSite serves the API of a config with an uk translation
+func apiSite(t *testing.T, origins ...string) http.Handler {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	translation := []byte("personal:\n  name: \"Яна\"\n  title: \"Розробниця\"\n")
+	if err := os.WriteFile(configPath, []byte(apiConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.uk.yaml"), translation, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	api, err := loadAPI(options{configPath: configPath}, origins)
+	if err != nil {
+		t.Fatalf("loadAPI() error = %v", err)
+	}
+	s := testSite(nil)
+	s.enableAPI(api)
+	return s.routes()
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+}
+
+func TestAPIResume(t *testing.T) {
+	routes := apiSite(t)
+
+	rec := get(t, routes, "/api/v1/resume")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Language") != "en" {
+		t.Fatalf("/api/v1/resume = %d in %q, want 200 in en", rec.Code, rec.Header().Get("Content-Language"))
+	}
+	var data components.ResumeData
+	decode(t, rec, &data)
+	if data.Personal.Name != "Jane Doe" || len(data.Skills) != 2 || len(data.Experience) != 1 {
+		t.Errorf("/api/v1/resume = %+v, want the full resume", data)
+	}
+	if data.Personal.Phone != "" {
+		t.Errorf("/api/v1/resume shows the private phone %q", data.Personal.Phone)
+	}
+
+	rec = get(t, routes, "/api/v1/resume?locale=uk")
+	decode(t, rec, &data)
+	if data.Personal.Name != "Яна" {
+		t.Errorf("/api/v1/resume?locale=uk name = %q, want the translation", data.Personal.Name)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/resume?locale=de":       http.StatusNotFound,
+		"/api/v1/resume?variant=unknown": http.StatusBadRequest,
+	} {
+		rec := get(t, routes, path)
+		var body apiError
+		decode(t, rec, &body)
+		if rec.Code != want || body.Error == "" {
+			t.Errorf("%s = %d %q, want %d with an error", path, rec.Code, body.Error, want)
+		}
+	}
+}
+
+func TestAPILists(t *testing.T) {
+	routes := apiSite(t)
+
+	var skills []components.SkillCategory
+	decode(t, get(t, routes, "/api/v1/skills?category=backend"), &skills)
+	if len(skills) != 1 || skills[0].Category != "Backend" {
+		t.Errorf("/api/v1/skills?category=backend = %+v, want the Backend category", skills)
+	}
+
+	rec := get(t, routes, "/api/v1/skills?category=cooking")
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+		t.Errorf("/api/v1/skills of an unknown category = %d %q, want an empty list", rec.Code, rec.Body.String())
+	}
+
+	decode(t, get(t, routes, "/api/v1/skills?variant=gamedev"), &skills)
+	if len(skills) != 2 {
+		t.Errorf("/api/v1/skills?variant=gamedev = %+v, want the untagged and gamedev categories", skills)
+	}
+
+	var experience []components.ExperienceItem
+	decode(t, get(t, routes, "/api/v1/experience"), &experience)
+	if len(experience) != 1 || experience[0].Company != "ACME" {
+		t.Errorf("/api/v1/experience = %+v", experience)
+	}
+}
+
+// corsRequest sends a request to the API from a page of origin
+func corsRequest(routes http.Handler, method, origin string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/api/v1/resume", nil)
+	r.Header.Set("Origin", origin)
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestAPICORS(t *testing.T) {
+	routes := apiSite(t, "https://team.example.com")
+
+	if got := corsRequest(routes, http.MethodGet, "https://team.example.com").Header().Get("Access-Control-Allow-Origin"); got != "https://team.example.com" {
+		t.Errorf("allowed origin got Access-Control-Allow-Origin %q", got)
+	}
+	if got := corsRequest(routes, http.MethodGet, "https://other.example.com").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("other origin got Access-Control-Allow-Origin %q", got)
+	}
+
+	rec := corsRequest(routes, http.MethodOptions, "https://team.example.com")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("preflight = %d %v, want 204 with the allowed methods", rec.Code, rec.Header())
+	}
+
+	open := apiSite(t, apiOrigins(" * ")...)
+	if got := corsRequest(open, http.MethodGet, "https://other.example.com").Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("any origin got Access-Control-Allow-Origin %q, want *", got)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	decode(t, get(t, apiSite(t), "/api/v1/openapi.json"), &doc)
+	if doc.OpenAPI == "" {
+		t.Error("openapi.json has no version")
+	}
+	for _, path := range []string{"/resume", "/experience", "/skills"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("openapi.json does not describe %s", path)
+		}
+	}
+}
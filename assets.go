@@ -0,0 +1,115 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// The stylesheet of the utility classes is generated from the templates
+//go:generate go run ./cmd/utilitycss -templates components -out static/css/utilities.css
+
+// embeddedStatic holds the static directory, so a single binary serves the
+// site and renders PDFs and preview cards from any working directory. The
+// templates need no embedding: templ compiles them into Go code.
+//
+//go:embed static
+var embeddedStatic embed.FS
+
+// staticFS returns the static assets. Files in overrideDir, when set, take
+// precedence over the embedded ones, so styles or the photo can be swapped
+// without rebuilding the binary.
+func staticFS(overrideDir string) (fs.FS, error) {
+	embedded, err := fs.Sub(embeddedStatic, "static")
+	if err != nil {
+		return nil, err
+	}
+	if overrideDir == "" {
+		return embedded, nil
+	}
+
+	info, err := os.Stat(overrideDir)
+	if err != nil {
+		return nil, fmt.Errorf("error opening static override directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("static override %s is not a directory", overrideDir)
+	}
+	return overlayFS{upper: os.DirFS(overrideDir), lower: embedded}, nil
+}
+
+// overlayFS serves files from upper and falls back to lower for the ones
+// upper does not have. Directory listings merge both.
+type overlayFS struct {
+	upper, lower fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.upper.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.lower.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories of upper list the entries of both layers
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		return &overlayDir{File: f, fsys: o, name: name}, nil
+	}
+	return f, nil
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if upperErr != nil && lowerErr != nil {
+		return nil, upperErr
+	}
+
+	entries := upper
+	for _, entry := range lower {
+		if !slices.ContainsFunc(upper, func(e fs.DirEntry) bool { return e.Name() == entry.Name() }) {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// overlayDir is a directory of overlayFS opened for reading
+type overlayDir struct {
+	fs.File
+	fsys    overlayFS
+	name    string
+	entries []fs.DirEntry
+	loaded  bool
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.loaded = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fairytale5571/cv_onopchenko/utilitycss"
+)
+
+func TestOverlayFS(t *testing.T) {
+	overlay := overlayFS{
+		upper: fstest.MapFS{
+			"css/style.css":   {Data: []byte("override")},
+			"img/profile.jpg": {Data: []byte("photo")},
+		},
+		lower: fstest.MapFS{
+			"css/style.css": {Data: []byte("embedded")},
+			"js/main.js":    {Data: []byte("script")},
+		},
+	}
+
+	for name, want := range map[string]string{
+		"css/style.css":   "override",
+		"img/profile.jpg": "photo",
+		"js/main.js":      "script",
+	} {
+		got, err := fs.ReadFile(overlay, name)
+		if err != nil {
+			t.Errorf("ReadFile(%q) error = %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("ReadFile(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	if err := fstest.TestFS(overlay, "css/style.css", "img/profile.jpg", "js/main.js"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEmbeddedStatic(t *testing.T) {
+	static, err := staticFS("")
+	if err != nil {
+		t.Fatalf("staticFS() error = %v", err)
+	}
+	for _, name := range []string{"css/style.css", "js/main.js", "fonts/Montserrat-Bold.ttf"} {
+		if _, err := fs.Stat(static, name); err != nil {
+			t.Errorf("embedded assets are missing %s: %v", name, err)
+		}
+	}
+}
+
+func TestUtilitiesUpToDate(t *testing.T) {
+	paths, err := filepath.Glob("components/*.templ")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no templates: %v", err)
+	}
+	var classes []string
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		classes = append(classes, utilitycss.Classes(src)...)
+	}
+
+	got, err := fs.ReadFile(embeddedStatic, "static/css/utilities.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append(utilitycss.Generate(classes), '\n'); !bytes.Equal(got, want) {
+		t.Error("static/css/utilities.css is stale, run go generate")
+	}
+}
+
+func TestPageIsSelfHosted(t *testing.T) {
+	body := get(t, testSite(nil).routes(), "/").Body.String()
+	for _, host := range []string{"cdn.tailwindcss.com", "cdn.jsdelivr.net", "fonts.googleapis.com", "fonts.gstatic.com"} {
+		if strings.Contains(body, host) {
+			t.Errorf("the page loads from %s", host)
+		}
+	}
+	if !strings.Contains(body, "/static/css/utilities.css") {
+		t.Error("the page does not link the utility stylesheet")
+	}
+}
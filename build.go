@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/compression"
+	"github.com/fairytale5571/cv_onopchenko/fingerprint"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+	"github.com/fairytale5571/cv_onopchenko/sitemap"
+)
+
+// runBuild генерирует статический сайт для GitHub Pages
+func runBuild(args []string) error {
+	var opts options
+	flags := flag.NewFlagSet("build", flag.ExitOnError)
+	opts.register(flags)
+	outDir := flags.String("out", DefaultOutputDir, "directory to write the site to")
+	staticDir := flags.String("static", "", "directory whose files override the embedded static assets")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	static, err := staticFS(*staticDir)
+	if err != nil {
+		return err
+	}
+
+	// Загружаем все страницы сайта; невалидный конфиг не публикуем
+	pages, err := sitePages(opts)
+	if err != nil {
+		return err
+	}
+
+	// Создаем директорию для статических файлов
+	err = os.MkdirAll(*outDir, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Статические файлы получают имена с хешем содержимого
+	assets, err := fingerprint.New(static, "/static/")
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint static files: %w", err)
+	}
+
+	// Копируем статические файлы под обоими именами
+	err = writeAssets(assets, filepath.Join(*outDir, "static"))
+	if err != nil {
+		return fmt.Errorf("failed to copy static files: %w", err)
+	}
+
+	for _, page := range pages {
+		dir := filepath.Join(*outDir, filepath.FromSlash(page.Page.Path))
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+
+		// Рендерим HTML в буфер
+		var buf bytes.Buffer
+		err = components.Resume(*page).Render(context.Background(), &buf)
+		if err != nil {
+			return fmt.Errorf("failed to render resume: %w", err)
+		}
+
+		// Записываем HTML в файл, ссылки на статику ведут на имена с хешем
+		err = writeCompressed(filepath.Join(dir, "index.html"), assets.Rewrite(buf.Bytes()))
+		if err != nil {
+			return fmt.Errorf("failed to write index.html: %w", err)
+		}
+
+		// Генерируем PDF файл тем же рендерером, что и /export-pdf
+		pdf, err := renderPDF(page)
+		if err != nil {
+			return fmt.Errorf("failed to generate PDF: %w", err)
+		}
+		err = writeCompressed(filepath.Join(*outDir, filepath.FromSlash(page.Page.PDFPath)), pdf)
+		if err != nil {
+			return fmt.Errorf("failed to write PDF: %w", err)
+		}
+	}
+
+	// Генерируем картинку для превью ссылок (Open Graph)
+	ogImage, err := renderOGImage(context.Background(), pages[0], static)
+	if errors.As(err, new(*photoError)) {
+		fmt.Fprintln(os.Stderr, err)
+	} else if err != nil {
+		return fmt.Errorf("failed to render preview image: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(*outDir, components.OGImagePath), ogImage, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write preview image: %w", err)
+	}
+
+	// Карта сайта, robots.txt и CNAME для поисковиков и своего домена
+	err = writeSiteFiles(*outDir, pages)
+	if err != nil {
+		return err
+	}
+
+	// Создаем файл .nojekyll для GitHub Pages
+	err = os.WriteFile(filepath.Join(*outDir, ".nojekyll"), []byte{}, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create .nojekyll file: %w", err)
+	}
+
+	fmt.Printf("Static site generated successfully in the '%s' directory\n", *outDir)
+	return nil
+}
+
+// sitePages загружает страницы сайта: язык из -locale в корне, остальные
+// переводы в /<язык>/, а варианты каждого перевода в <вариант>/, если
+// -variant не выбрал один. Приватные поля в публичную сборку не попадают.
+func sitePages(opts options) ([]*components.ResumeData, error) {
+	locales, err := resume.Locales(opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+	root := cmp.Or(opts.locale, components.DefaultLocale)
+	locales = slices.DeleteFunc(locales, func(locale string) bool { return locale == root })
+	locales = append([]string{root}, locales...)
+
+	var pages []*components.ResumeData
+	for _, locale := range locales {
+		prefix := "/"
+		if locale != root {
+			prefix = "/" + locale + "/"
+		}
+
+		o := opts
+		o.locale = locale
+		// Варианты объявлены в полном резюме, которое загружается первым
+		variants := []string{opts.variant}
+		for i := 0; i < len(variants); i++ {
+			o.variant = variants[i]
+			data, err := o.loadValid()
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 && opts.variant == "" {
+				for _, variant := range data.Variants {
+					variants = append(variants, variant.Name)
+				}
+			}
+
+			page := resume.Redact(*data)
+			page.Page.Path = prefix
+			if i > 0 {
+				page.Page.Path += variants[i] + "/"
+			}
+			page.Page.PDFPath = page.Page.Path + "resume.pdf"
+			if page.Page.Path == "/" {
+				page.Page.PDFPath = "/static/resume.pdf"
+			}
+			pages = append(pages, &page)
+		}
+	}
+	return pages, nil
+}
+
+// writeSiteFiles записывает robots.txt, а при заданном site.baseURL еще
+// sitemap.xml со всеми страницами и их PDF и CNAME своего домена
+func writeSiteFiles(outDir string, pages []*components.ResumeData) error {
+	base := components.BaseURL(*pages[0])
+	if base == "" {
+		err := os.WriteFile(filepath.Join(outDir, "robots.txt"), sitemap.Robots(""), 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write robots.txt: %w", err)
+		}
+		return nil
+	}
+
+	var locs []string
+	for _, page := range pages {
+		locs = append(locs, components.CanonicalURL(*page), base+page.Page.PDFPath)
+	}
+	doc, err := sitemap.Marshal(locs)
+	if err != nil {
+		return fmt.Errorf("failed to generate sitemap: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(outDir, "sitemap.xml"), doc, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write sitemap.xml: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(outDir, "robots.txt"), sitemap.Robots(base+"/sitemap.xml"), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write robots.txt: %w", err)
+	}
+
+	// Адрес на github.io не нуждается в CNAME
+	u, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(u.Hostname(), ".github.io") {
+		err = os.WriteFile(filepath.Join(outDir, "CNAME"), []byte(u.Hostname()+"\n"), 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create CNAME file: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeAssets записывает статические файлы в директорию dst
+func writeAssets(assets *fingerprint.Assets, dst string) error {
+	for _, name := range assets.Names() {
+		data, _ := assets.ReadFile(name)
+		dstPath := filepath.Join(dst, filepath.FromSlash(name))
+
+		// Создаем поддиректории
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dstPath, data, 0644); err != nil {
+			return err
+		}
+
+		// Сжатые копии, которые хостинг отдает вместо файла
+		for _, encoding := range compression.Encodings {
+			if encoded, ok := assets.Encoded(name, encoding); ok {
+				if err := os.WriteFile(dstPath+compression.Extension(encoding), encoded, 0644); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeCompressed записывает файл вместе с его сжатыми копиями .br и .gz
+func writeCompressed(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	for _, encoding := range compression.Encodings {
+		encoded, err := compression.Compress(encoding, data)
+		if err != nil {
+			return err
+		}
+		// Сжатие, которое не уменьшает файл, не нужно
+		if len(encoded) >= len(data) {
+			continue
+		}
+		if err := os.WriteFile(path+compression.Extension(encoding), encoded, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+const buildConfig = `site:
+  baseURL: "https://cv.example.com/"
+personal:
+  name: "Jane Doe"
+  title: "Developer"
+  phone: "+1 555 0100"
+variants:
+  - name: backend
+private:
+  - personal.phone
+`
+
+// buildOptions writes a config with an uk translation for the build
+func buildOptions(t *testing.T, config string) options {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.uk.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return options{configPath: configPath, theme: components.ThemeSystem}
+}
+
+func TestSitePages(t *testing.T) {
+	opts := buildOptions(t, buildConfig)
+	pages, err := sitePages(opts)
+	if err != nil {
+		t.Fatalf("sitePages() error = %v", err)
+	}
+
+	var paths []string
+	for _, page := range pages {
+		paths = append(paths, page.Page.Path+" "+page.Page.PDFPath)
+		if page.Personal.Phone != "" {
+			t.Errorf("page %s shows the private phone", page.Page.Path)
+		}
+	}
+	want := "/ /static/resume.pdf, /backend/ /backend/resume.pdf, /uk/ /uk/resume.pdf, /uk/backend/ /uk/backend/resume.pdf"
+	if got := strings.Join(paths, ", "); got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+
+	// A chosen locale and variant make the root page and only one per locale
+	opts.locale, opts.variant = "uk", "backend"
+	pages, err = sitePages(opts)
+	if err != nil {
+		t.Fatalf("sitePages() error = %v", err)
+	}
+	if len(pages) != 2 || pages[0].Page.Path != "/" || pages[0].Page.Locale != "uk" || pages[1].Page.Path != "/en/" {
+		t.Errorf("pages of uk backend = %+v", pages)
+	}
+}
+
+func TestWriteSiteFiles(t *testing.T) {
+	pages, err := sitePages(buildOptions(t, buildConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := writeSiteFiles(dir, pages); err != nil {
+		t.Fatalf("writeSiteFiles() error = %v", err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if got := read("CNAME"); got != "cv.example.com\n" {
+		t.Errorf("CNAME = %q", got)
+	}
+	if got := read("robots.txt"); !strings.Contains(got, "Sitemap: https://cv.example.com/sitemap.xml") {
+		t.Errorf("robots.txt = %q, want the sitemap", got)
+	}
+	for _, want := range []string{"https://cv.example.com/uk/backend/", "https://cv.example.com/static/resume.pdf"} {
+		if got := read("sitemap.xml"); !strings.Contains(got, "<loc>"+want+"</loc>") {
+			t.Errorf("sitemap.xml does not list %s:\n%s", want, got)
+		}
+	}
+
+	// Without a base URL there is nothing absolute to list
+	pages, err = sitePages(buildOptions(t, strings.Replace(buildConfig, "https://cv.example.com/", "", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir = t.TempDir()
+	if err := writeSiteFiles(dir, pages); err != nil {
+		t.Fatalf("writeSiteFiles() error = %v", err)
+	}
+	for _, name := range []string{"sitemap.xml", "CNAME"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s written without a base URL", name)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/linkcheck"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+)
+
+// DefaultLinkCachePath is where check-links keeps the links that passed
+const DefaultLinkCachePath = "links-cache.json"
+
+// runCheckLinks checks every link of the resume and reports the dead and
+// redirected ones. It fails when a link is dead.
+func runCheckLinks(args []string) error {
+	var opts options
+	flags := flag.NewFlagSet("check-links", flag.ExitOnError)
+	opts.register(flags)
+	checker := linkcheck.NewChecker()
+	flags.IntVar(&checker.Concurrency, "concurrency", linkcheck.DefaultConcurrency, "how many links to check at once")
+	flags.IntVar(&checker.Retries, "retries", linkcheck.DefaultRetries, "how many times to retry a link that fails with a network or server error")
+	timeout := flags.Duration("timeout", linkcheck.DefaultTimeout, "timeout of each request")
+	cachePath := flags.String("cache", DefaultLinkCachePath, "file of the links that passed; empty disables the cache")
+	cacheTTL := flags.Duration("cache-ttl", linkcheck.DefaultCacheTTL, "how long a passed link is not checked again")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	links, err := resumeLinks(opts)
+	if err != nil {
+		return err
+	}
+
+	checker.Client = &http.Client{Timeout: *timeout}
+	if *cachePath != "" {
+		checker.Cache, err = linkcheck.OpenCache(*cachePath, *cacheTTL)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results := checker.Check(ctx, links)
+	if checker.Cache != nil {
+		if err := checker.Cache.Save(); err != nil {
+			return err
+		}
+	}
+
+	dead := reportLinks(os.Stdout, results)
+	if dead > 0 {
+		return fmt.Errorf("%d dead links", dead)
+	}
+	return nil
+}
+
+// resumeLinks collects the links of the config and of the rendered page,
+// in every translation unless -locale picks one
+func resumeLinks(opts options) ([]string, error) {
+	locales := []string{opts.locale}
+	if opts.locale == "" {
+		var err error
+		locales, err = resume.Locales(opts.configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lists [][]string
+	for _, locale := range locales {
+		o := opts
+		o.locale = locale
+		data, err := o.load()
+		if err != nil {
+			return nil, err
+		}
+
+		var page bytes.Buffer
+		if err := components.Resume(*data).Render(context.Background(), &page); err != nil {
+			return nil, fmt.Errorf("failed to render resume: %w", err)
+		}
+		lists = append(lists, linkcheck.Collect(*data), linkcheck.Extract(page.Bytes()))
+	}
+	return linkcheck.Merge(lists...), nil
+}
+
+// reportLinks prints the dead and redirected links with a summary and
+// returns the number of dead ones
+func reportLinks(w io.Writer, results []linkcheck.Result) int {
+	dead, redirected, cached := 0, 0, 0
+	for _, result := range linkcheck.Problems(results) {
+		switch result.Status {
+		case linkcheck.Dead:
+			dead++
+			reason := fmt.Sprintf("%d %s", result.Code, http.StatusText(result.Code))
+			if result.Error != "" {
+				reason = result.Error
+			}
+			fmt.Fprintf(w, "dead        %s (%s)\n", result.URL, reason)
+		case linkcheck.Redirected:
+			redirected++
+			fmt.Fprintf(w, "redirected  %s -> %s\n", result.URL, result.Location)
+		}
+	}
+	for _, result := range results {
+		if result.Cached {
+			cached++
+		}
+	}
+	fmt.Fprintf(w, "checked %d links (%d from the cache): %d dead, %d redirected\n", len(results), cached, dead, redirected)
+	return dead
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/docx"
+	"github.com/fairytale5571/cv_onopchenko/markdown"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+)
+
+// Formats accepted by export -format
+const (
+	FormatPDF      = "pdf"
+	FormatMarkdown = "md"
+	FormatDOCX     = "docx"
+)
+
+const usage = `Usage: cv <command> [flags]
+
+Commands:
+  serve        serve the resume over HTTP (default)
+  build        generate the static site
+  export       write the resume as PDF, Markdown or DOCX
+  validate     check the config for errors
+  check-links  report the dead and redirected links of the resume
+  import       convert a JSON Resume file into the config layout
+  share        create, list and revoke per-recipient share links
+  access       print a link that reveals the private fields
+
+Run "cv <command> -h" for the flags of a command.
+`
+
+// run dispatches to the subcommand named by the first argument
+func run(args []string) error {
+	command, rest := "serve", args
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, rest = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		return runServe(rest)
+	case "build":
+		return runBuild(rest)
+	case "export":
+		return runExport(rest)
+	case "validate":
+		return runValidate(rest)
+	case "check-links":
+		return runCheckLinks(rest)
+	case "import":
+		return runImport(rest)
+	case "share":
+		return runShare(rest)
+	case "access":
+		return runAccess(rest)
+	case "help":
+		fmt.Print(usage)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command %q", command)
+}
+
+// options are the flags shared by the commands that render the resume
+type options struct {
+	configPath string
+	variant    string
+	locale     string
+	theme      string
+}
+
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.configPath, "config", DefaultConfigPath, "path to the resume config")
+	flags.StringVar(&o.variant, "variant", "", "render only the entries of this variant")
+	flags.StringVar(&o.locale, "locale", "", "load the translated config, e.g. uk reads config.uk.yaml")
+	flags.StringVar(&o.theme, "theme", components.ThemeSystem, "default color theme: system, light or dark")
+}
+
+// load reads the config for the chosen locale and narrows it to the variant
+func (o *options) load() (*components.ResumeData, error) {
+	return o.loadVariant(o.variant)
+}
+
+// loadVariant is load with another variant, empty for the full resume
+func (o *options) loadVariant(name string) (*components.ResumeData, error) {
+	if !components.IsTheme(o.theme) {
+		return nil, fmt.Errorf("unknown theme %q", o.theme)
+	}
+
+	data, err := resume.Load(o.configPath, o.locale)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := resume.ApplyVariant(*data, name)
+	if err != nil {
+		return nil, err
+	}
+	variant.Page.Theme = o.theme
+	return &variant, nil
+}
+
+// loadValid is load for the commands that publish the resume: they refuse an
+// invalid config instead of shipping it
+func (o *options) loadValid() (*components.ResumeData, error) {
+	data, err := o.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := resume.Validate(*data); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", resume.LocalePath(o.configPath, o.locale), err)
+	}
+	return data, nil
+}
+
+// runExport writes the resume in a single document format
+func runExport(args []string) error {
+	var opts options
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	opts.register(flags)
+	format := flags.String("format", FormatPDF, "document format: pdf, md or docx")
+	outPath := flags.String("out", "", `output file, "-" for stdout (default "resume.<format>")`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	resumeData, err := opts.loadValid()
+	if err != nil {
+		return err
+	}
+
+	var doc []byte
+	switch *format {
+	case FormatPDF:
+		doc, err = renderPDF(resumeData)
+	case FormatMarkdown:
+		doc = markdown.Render(*resumeData)
+	case FormatDOCX:
+		doc, err = docx.Render(*resumeData)
+	default:
+		return fmt.Errorf("unknown format %q, want pdf, md or docx", *format)
+	}
+	if err != nil {
+		return fmt.Errorf("error rendering %s: %w", *format, err)
+	}
+
+	switch *outPath {
+	case "-":
+		_, err = os.Stdout.Write(doc)
+		return err
+	case "":
+		*outPath = "resume." + *format
+	}
+	if dir := filepath.Dir(*outPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	return os.WriteFile(*outPath, doc, 0644)
+}
+
+// runValidate checks the config of every locale given, or of the default one
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := flags.String("config", DefaultConfigPath, "path to the resume config")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: validate [-config config.yaml] [locale...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	locales := flags.Args()
+	if len(locales) == 0 {
+		locales = []string{""}
+	}
+
+	invalid := false
+	for _, locale := range locales {
+		path := resume.LocalePath(*configPath, locale)
+		data, err := resume.Load(*configPath, locale)
+		if err == nil {
+			err = resume.Validate(*data)
+		}
+		if err != nil {
+			invalid = true
+			fmt.Fprintf(os.Stderr, "%s:\n", path)
+			for _, line := range strings.Split(err.Error(), "\n") {
+				fmt.Fprintf(os.Stderr, "  %s\n", line)
+			}
+			continue
+		}
+		fmt.Printf("%s is valid\n", path)
+	}
+
+	if invalid {
+		return errors.New("config validation failed")
+	}
+	return nil
+}
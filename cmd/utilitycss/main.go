@@ -0,0 +1,51 @@
+// Command utilitycss writes the stylesheet of the utility classes used by
+// the templates. It runs from go generate in the root of the module:
+//
+//	go run ./cmd/utilitycss -templates components -out static/css/utilities.css
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fairytale5571/cv_onopchenko/utilitycss"
+)
+
+func main() {
+	templates := flag.String("templates", "components", "directory of the .templ files to scan")
+	out := flag.String("out", "", "stylesheet to write, stdout when empty")
+	flag.Parse()
+
+	if err := run(*templates, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(templates, out string) error {
+	paths, err := filepath.Glob(filepath.Join(templates, "*.templ"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no templates in %s", templates)
+	}
+
+	var classes []string
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		classes = append(classes, utilitycss.Classes(src)...)
+	}
+
+	css := append(utilitycss.Generate(classes), '\n')
+	if out == "" {
+		_, err := os.Stdout.Write(css)
+		return err
+	}
+	return os.WriteFile(out, css, 0644)
+}
@@ -0,0 +1,56 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.857
+package components
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+// ContactForm posts a message to /contact. The website field is a trap for
+// bots and stays hidden from people and screen readers; token tells the
+// server when the page was loaded.
+func ContactForm(token string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<section id=\"contact\" class=\"p-8 border-t border-border\"><h3 class=\"text-xl font-semibold mb-6 text-primary border-b-2 border-primary pb-2 inline-block\">Contact</h3><form id=\"contact-form\" method=\"post\" action=\"/contact\" class=\"grid grid-cols-1 md:grid-cols-2 gap-4 max-w-2xl\"><input type=\"hidden\" name=\"token\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var2 string
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(token)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/contact.templ`, Line: 10, Col: 50}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"><div style=\"position: absolute; left: -10000px;\" aria-hidden=\"true\"><label for=\"contact-website\">Website</label> <input id=\"contact-website\" type=\"text\" name=\"website\" tabindex=\"-1\" autocomplete=\"off\"></div><label class=\"flex flex-col gap-1 text-sm text-foreground\">Name <input type=\"text\" name=\"name\" required maxlength=\"200\" autocomplete=\"name\" class=\"bg-card border border-border rounded-md px-3 py-2\"></label> <label class=\"flex flex-col gap-1 text-sm text-foreground\">Email <input type=\"email\" name=\"email\" required autocomplete=\"email\" class=\"bg-card border border-border rounded-md px-3 py-2\"></label> <label class=\"flex flex-col gap-1 text-sm text-foreground md:col-span-2\">Message <textarea name=\"message\" required maxlength=\"5000\" rows=\"5\" class=\"bg-card border border-border rounded-md px-3 py-2\"></textarea></label><div class=\"md:col-span-2 flex items-center gap-4\"><button type=\"submit\" class=\"bg-primary text-primary-foreground rounded-md px-4 py-2 shadow-sm hover:shadow-md transition-shadow\">Send</button><p id=\"contact-status\" class=\"text-sm text-muted-foreground\" role=\"status\"></p></div></form></section>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -8,7 +8,7 @@ package components
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-func Layout(title string) templ.Component {
+func Layout(data ResumeData) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -29,20 +29,265 @@ func Layout(title string) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!doctype html><html lang=\"en\" class=\"light\"><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><meta http-equiv=\"Cache-Control\" content=\"no-cache, no-store, must-revalidate\"><meta http-equiv=\"Pragma\" content=\"no-cache\"><meta http-equiv=\"Expires\" content=\"0\"><title>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<!doctype html>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(title)
+		var templ_7745c5c3_Var2 = []any{themeClass(data.Page)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var2...)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 12, Col: 17}
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<html lang=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(htmlLang(data.Page))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 5, Col: 33}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\" class=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var2).String())
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 1, Col: 0}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\" data-theme=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(defaultTheme(data.Page))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 5, Col: 104}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\"><head><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"><title>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 string
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(pageTitle(data))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 9, Col: 27}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</title><script>\n\t\t\t\t// Apply the saved or default theme before the first paint\n\t\t\t\t(function () {\n\t\t\t\t\tvar root = document.documentElement;\n\t\t\t\t\tvar saved = localStorage.getItem('darkMode');\n\t\t\t\t\tvar dark = root.dataset.theme === 'dark' ||\n\t\t\t\t\t\t(root.dataset.theme === 'system' && window.matchMedia('(prefers-color-scheme: dark)').matches);\n\t\t\t\t\tif (saved !== null) {\n\t\t\t\t\t\tdark = saved === 'true';\n\t\t\t\t\t}\n\t\t\t\t\troot.classList.toggle('dark', dark);\n\t\t\t\t})();\n\t\t\t</script><meta name=\"description\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var7 string
+		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(metaDescription(data.Personal.Summary))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 23, Col: 76}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\"><meta name=\"author\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var8 string
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(data.Personal.Name)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 24, Col: 51}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if CanonicalURL(data) != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<link rel=\"canonical\" href=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var9 string
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(CanonicalURL(data))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 26, Col: 51}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<!-- Open Graph / Twitter cards for link previews --><meta property=\"og:type\" content=\"profile\"><meta property=\"og:title\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var10 string
+		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(pageTitle(data))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 30, Col: 54}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\"><meta property=\"og:description\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var11 string
+		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(metaDescription(data.Personal.Summary))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 31, Col: 83}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if SiteURL(data) != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<meta property=\"og:url\" content=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var12 string
+			templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(SiteURL(data))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 33, Col: 51}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<meta property=\"og:image\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var13 string
+		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(ogImageURL(data))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 35, Col: 55}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\"><meta property=\"og:image:type\" content=\"image/png\"><meta property=\"og:image:width\" content=\"1200\"><meta property=\"og:image:height\" content=\"630\"><meta property=\"og:image:alt\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var14 string
+		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(pageTitle(data))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 39, Col: 58}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"><meta property=\"profile:first_name\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var15 string
+		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(firstName(data.Personal.Name))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 40, Col: 78}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\"><meta property=\"profile:last_name\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var16 string
+		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(lastName(data.Personal.Name))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 41, Col: 76}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\"><meta name=\"twitter:card\" content=\"summary_large_image\"><meta name=\"twitter:title\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var17 string
+		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(pageTitle(data))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 43, Col: 55}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\"><meta name=\"twitter:description\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var18 string
+		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(metaDescription(data.Personal.Summary))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 44, Col: 84}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "\"><meta name=\"twitter:image\" content=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var19 string
+		templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(ogImageURL(data))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/layout.templ`, Line: 45, Col: 56}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\"><!-- Structured data for search engines -->")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		templ_7745c5c3_Err = templ.JSONScript("person-jsonld", personJSONLD(data)).WithType("application/ld+json").Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</title><link rel=\"stylesheet\" href=\"/static/css/style.css?v=1.7\"><link rel=\"stylesheet\" href=\"https://fonts.googleapis.com/css2?family=Montserrat:wght@400;500;600;700&amp;display=swap\"><style>\n\t\t\t\t/* Встроенные стили, которые гарантированно применятся */\n\t\t\t\t.container {\n\t\t\t\t\tmax-width: 900px !important;\n\t\t\t\t\twidth: 75% !important;\n\t\t\t\t\tmargin: 0 auto;\n\t\t\t\t\tpadding: 2rem;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Плавная анимация для всех элементов при смене темы */\n\t\t\t\t*, *::before, *::after {\n\t\t\t\t\ttransition: background-color 0.3s ease, color 0.3s ease, border-color 0.3s ease, box-shadow 0.3s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Оптимизация анимации для списков */\n\t\t\t\tul, li {\n\t\t\t\t\ttransition: color 0.3s ease !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Отключаем анимацию для некоторых свойств, чтобы избежать задержек */\n\t\t\t\t.list-disc, .pl-5, .space-y-1, .mb-4 {\n\t\t\t\t\ttransition: none !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Специальные стили для оптимизированных списков */\n\t\t\t\t.theme-optimized-list {\n\t\t\t\t\ttransition: none !important;\n\t\t\t\t\twill-change: contents;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.theme-optimized-item {\n\t\t\t\t\ttransition: color 0.15s ease !important;\n\t\t\t\t\twill-change: color;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация только для цвета текста в списках */\n\t\t\t\t.list-disc li {\n\t\t\t\t\ttransition: color 0.3s ease !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация для карточек опыта работы */\n\t\t\t\t.experience-card {\n\t\t\t\t\ttransition: transform 0.2s ease, box-shadow 0.2s ease, background-color 0.3s ease, border-color 0.3s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.experience-card:hover {\n\t\t\t\t\ttransform: translateY(-3px);\n\t\t\t\t\tbox-shadow: 0 10px 15px -3px rgba(0, 0, 0, 0.1), 0 4px 6px -2px rgba(0, 0, 0, 0.05) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация для мини-карточек технологий */\n\t\t\t\t.tech-badge {\n\t\t\t\t\ttransition: transform 0.2s ease, background-color 0.2s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.tech-badge:hover {\n\t\t\t\t\ttransform: translateY(-2px);\n\t\t\t\t\tbackground-color: var(--badge-hover-bg, hsl(var(--primary))) !important;\n\t\t\t\t\tcolor: var(--badge-hover-text, hsl(var(--primary-foreground))) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация для мини-карточек навыков */\n\t\t\t\t.skill-badge {\n\t\t\t\t\ttransition: transform 0.2s ease, background-color 0.2s ease;\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.skill-badge:hover {\n\t\t\t\t\ttransform: translateY(-2px);\n\t\t\t\t\tbackground-color: hsl(var(--primary)) !important;\n\t\t\t\t\tcolor: hsl(var(--primary-foreground)) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Цвета шапки */\n\t\t\t\theader {\n\t\t\t\t\tbackground-color: hsl(210, 100%, 20%) !important;\n\t\t\t\t\tcolor: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.dark header {\n\t\t\t\t\tbackground-color: hsl(210, 80%, 15%) !important;\n\t\t\t\t\tcolor: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Гарантируем, что весь текст в шапке будет белым */\n\t\t\t\theader h1, \n\t\t\t\theader h2, \n\t\t\t\theader p, \n\t\t\t\theader a, \n\t\t\t\theader span {\n\t\t\t\t\tcolor: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\theader a {\n\t\t\t\t\topacity: 0.9;\n\t\t\t\t\ttransition: opacity 0.2s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\theader a:hover {\n\t\t\t\t\ttext-decoration: underline;\n\t\t\t\t\topacity: 1;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Убеждаемся, что иконки тоже белые */\n\t\t\t\theader svg {\n\t\t\t\t\tstroke: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.skills-container {\n\t\t\t\t\tgrid-template-columns: repeat(auto-fill, minmax(250px, 1fr)) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.languages-list {\n\t\t\t\t\tgrid-template-columns: repeat(auto-fill, minmax(180px, 1fr)) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t@media (max-width: 1200px) {\n\t\t\t\t\t.container {\n\t\t\t\t\t\twidth: 85% !important;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t@media (max-width: 992px) {\n\t\t\t\t\t.container {\n\t\t\t\t\t\twidth: 90% !important;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t@media (max-width: 768px) {\n\t\t\t\t\t.container {\n\t\t\t\t\t\twidth: 100% !important;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t</style><script src=\"https://cdn.tailwindcss.com\"></script><script>\n\t\t\t\ttailwind.config = {\n\t\t\t\t\tdarkMode: 'class',\n\t\t\t\t\ttheme: {\n\t\t\t\t\t\textend: {\n\t\t\t\t\t\t\tcolors: {\n\t\t\t\t\t\t\t\tborder: \"hsl(var(--border))\",\n\t\t\t\t\t\t\t\tinput: \"hsl(var(--input))\",\n\t\t\t\t\t\t\t\tring: \"hsl(var(--ring))\",\n\t\t\t\t\t\t\t\tbackground: \"hsl(var(--background))\",\n\t\t\t\t\t\t\t\tforeground: \"hsl(var(--foreground))\",\n\t\t\t\t\t\t\t\tprimary: {\n\t\t\t\t\t\t\t\t\tDEFAULT: \"hsl(var(--primary))\",\n\t\t\t\t\t\t\t\t\tforeground: \"hsl(var(--primary-foreground))\",\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\tsecondary: {\n\t\t\t\t\t\t\t\t\tDEFAULT: \"hsl(var(--secondary))\",\n\t\t\t\t\t\t\t\t\tforeground: \"hsl(var(--secondary-foreground))\",\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\tdestructive: {\n\t\t\t\t\t\t\t\t\tDEFAULT: \"hsl(var(--destructive))\",\n\t\t\t\t\t\t\t\t\tforeground: \"hsl(var(--destructive-foreground))\",\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\tmuted: {\n\t\t\t\t\t\t\t\t\tDEFAULT: \"hsl(var(--muted))\",\n\t\t\t\t\t\t\t\t\tforeground: \"hsl(var(--muted-foreground))\",\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\taccent: {\n\t\t\t\t\t\t\t\t\tDEFAULT: \"hsl(var(--accent))\",\n\t\t\t\t\t\t\t\t\tforeground: \"hsl(var(--accent-foreground))\",\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\tcard: {\n\t\t\t\t\t\t\t\t\tDEFAULT: \"hsl(var(--card))\",\n\t\t\t\t\t\t\t\t\tforeground: \"hsl(var(--card-foreground))\",\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\tborderRadius: {\n\t\t\t\t\t\t\t\tlg: \"var(--radius)\",\n\t\t\t\t\t\t\t\tmd: \"calc(var(--radius) - 2px)\",\n\t\t\t\t\t\t\t\tsm: \"calc(var(--radius) - 4px)\",\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\tfontFamily: {\n\t\t\t\t\t\t\t\tsans: ['Montserrat', 'sans-serif'],\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\tfontWeight: {\n\t\t\t\t\t\t\t\tnormal: 500,\n\t\t\t\t\t\t\t\tmedium: 500,\n\t\t\t\t\t\t\t\tsemibold: 600,\n\t\t\t\t\t\t\t\tbold: 700,\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t</script><script defer src=\"https://cdn.jsdelivr.net/npm/alpinejs@3.x.x/dist/cdn.min.js\"></script></head><body class=\"bg-background text-foreground font-sans font-medium\"><div class=\"container mx-auto px-4 py-8\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<link rel=\"stylesheet\" href=\"/static/css/style.css\"><style>\n\t\t\t\t/* Встроенные стили, которые гарантированно применятся */\n\t\t\t\t.container {\n\t\t\t\t\tmax-width: 900px !important;\n\t\t\t\t\twidth: 75% !important;\n\t\t\t\t\tmargin: 0 auto;\n\t\t\t\t\tpadding: 2rem;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Плавная анимация для всех элементов при смене темы */\n\t\t\t\t*, *::before, *::after {\n\t\t\t\t\ttransition: background-color 0.3s ease, color 0.3s ease, border-color 0.3s ease, box-shadow 0.3s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Оптимизация анимации для списков */\n\t\t\t\tul, li {\n\t\t\t\t\ttransition: color 0.3s ease !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Отключаем анимацию для некоторых свойств, чтобы избежать задержек */\n\t\t\t\t.list-disc, .pl-5, .space-y-1, .mb-4 {\n\t\t\t\t\ttransition: none !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Специальные стили для оптимизированных списков */\n\t\t\t\t.theme-optimized-list {\n\t\t\t\t\ttransition: none !important;\n\t\t\t\t\twill-change: contents;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.theme-optimized-item {\n\t\t\t\t\ttransition: color 0.15s ease !important;\n\t\t\t\t\twill-change: color;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация только для цвета текста в списках */\n\t\t\t\t.list-disc li {\n\t\t\t\t\ttransition: color 0.3s ease !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация для карточек опыта работы */\n\t\t\t\t.experience-card {\n\t\t\t\t\ttransition: transform 0.2s ease, box-shadow 0.2s ease, background-color 0.3s ease, border-color 0.3s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.experience-card:hover {\n\t\t\t\t\ttransform: translateY(-3px);\n\t\t\t\t\tbox-shadow: 0 10px 15px -3px rgba(0, 0, 0, 0.1), 0 4px 6px -2px rgba(0, 0, 0, 0.05) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация для мини-карточек технологий */\n\t\t\t\t.tech-badge {\n\t\t\t\t\ttransition: transform 0.2s ease, background-color 0.2s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.tech-badge:hover {\n\t\t\t\t\ttransform: translateY(-2px);\n\t\t\t\t\tbackground-color: var(--badge-hover-bg, hsl(var(--primary))) !important;\n\t\t\t\t\tcolor: var(--badge-hover-text, hsl(var(--primary-foreground))) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Анимация для мини-карточек навыков */\n\t\t\t\t.skill-badge {\n\t\t\t\t\ttransition: transform 0.2s ease, background-color 0.2s ease;\n\t\t\t\t\tcursor: pointer;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.skill-badge:hover {\n\t\t\t\t\ttransform: translateY(-2px);\n\t\t\t\t\tbackground-color: hsl(var(--primary)) !important;\n\t\t\t\t\tcolor: hsl(var(--primary-foreground)) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Цвета шапки */\n\t\t\t\theader {\n\t\t\t\t\tbackground-color: hsl(210, 100%, 20%) !important;\n\t\t\t\t\tcolor: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.dark header {\n\t\t\t\t\tbackground-color: hsl(210, 80%, 15%) !important;\n\t\t\t\t\tcolor: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Гарантируем, что весь текст в шапке будет белым */\n\t\t\t\theader h1, \n\t\t\t\theader h2, \n\t\t\t\theader p, \n\t\t\t\theader a, \n\t\t\t\theader span {\n\t\t\t\t\tcolor: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\theader a {\n\t\t\t\t\topacity: 0.9;\n\t\t\t\t\ttransition: opacity 0.2s ease;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\theader a:hover {\n\t\t\t\t\ttext-decoration: underline;\n\t\t\t\t\topacity: 1;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t/* Убеждаемся, что иконки тоже белые */\n\t\t\t\theader svg {\n\t\t\t\t\tstroke: white !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.skills-container {\n\t\t\t\t\tgrid-template-columns: repeat(auto-fill, minmax(250px, 1fr)) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t.languages-list {\n\t\t\t\t\tgrid-template-columns: repeat(auto-fill, minmax(180px, 1fr)) !important;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t@media (max-width: 1200px) {\n\t\t\t\t\t.container {\n\t\t\t\t\t\twidth: 85% !important;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t@media (max-width: 992px) {\n\t\t\t\t\t.container {\n\t\t\t\t\t\twidth: 90% !important;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t@media (max-width: 768px) {\n\t\t\t\t\t.container {\n\t\t\t\t\t\twidth: 100% !important;\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t</style><!-- Utility classes of the templates, generated by cmd/utilitycss --><link rel=\"stylesheet\" href=\"/static/css/utilities.css\"></head><body class=\"bg-background text-foreground font-sans font-medium\"><div class=\"container mx-auto px-4 py-8\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -50,7 +295,7 @@ func Layout(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</div><script src=\"/static/js/main.js\"></script></body></html>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</div><script src=\"/static/js/main.js\"></script></body></html>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
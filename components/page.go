@@ -0,0 +1,76 @@
+package components
+
+// Themes accepted by the -theme flag
+const (
+	// ThemeSystem follows the visitor's prefers-color-scheme setting
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// DefaultLocale is the language of config.yaml itself
+const DefaultLocale = "en"
+
+// PageOptions are rendering options that do not come from config.yaml
+type PageOptions struct {
+	// Locale is the language of the loaded config, used for the lang attribute
+	Locale string
+	// Theme is the color theme shown until the visitor picks one with the toggle
+	Theme string
+	// Variant is the name of the applied variant, empty for the full resume
+	Variant string
+	// PDFPath is where the export button downloads the PDF from, /export-pdf
+	// when empty
+	PDFPath string
+	// ContactToken is rendered into the contact form, which is shown only
+	// when the server accepts messages
+	ContactToken string
+	// Path is where the page is published below the base URL, such as
+	// /uk/backend/; / when empty
+	Path string
+}
+
+// DefaultPDFPath is the PDF endpoint of the server
+const DefaultPDFPath = "/export-pdf"
+
+// IsTheme reports whether theme is one of the supported themes
+func IsTheme(theme string) bool {
+	switch theme {
+	case ThemeSystem, ThemeLight, ThemeDark:
+		return true
+	}
+	return false
+}
+
+// htmlLang is the lang attribute of the page
+func htmlLang(page PageOptions) string {
+	if page.Locale == "" {
+		return DefaultLocale
+	}
+	return page.Locale
+}
+
+// themeClass is the class of the root element before any script runs
+func themeClass(page PageOptions) string {
+	if page.Theme == ThemeDark {
+		return ThemeDark
+	}
+	return ThemeLight
+}
+
+// defaultTheme is exposed to the theme script as data-theme; system lets the
+// script consult prefers-color-scheme
+func defaultTheme(page PageOptions) string {
+	if page.Theme == "" {
+		return ThemeSystem
+	}
+	return page.Theme
+}
+
+// pdfPath is the download address of the export button
+func pdfPath(page PageOptions) string {
+	if page.PDFPath == "" {
+		return DefaultPDFPath
+	}
+	return page.PDFPath
+}
@@ -0,0 +1,94 @@
+package components
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Sections of the resume body that layout.sections may list
+const (
+	SectionSkills         = "skills"
+	SectionExperience     = "experience"
+	SectionEducation      = "education"
+	SectionProjects       = "projects"
+	SectionContributions  = "contributions"
+	SectionPublications   = "publications"
+	SectionTalks          = "talks"
+	SectionCertifications = "certifications"
+	SectionLanguages      = "languages"
+)
+
+// DefaultSections is the order of the sections when layout.sections is empty
+var DefaultSections = []string{
+	SectionSkills,
+	SectionExperience,
+	SectionEducation,
+	SectionProjects,
+	SectionContributions,
+	SectionPublications,
+	SectionTalks,
+	SectionCertifications,
+	SectionLanguages,
+}
+
+// IsSection reports whether name is one of the sections of the resume body
+func IsSection(name string) bool {
+	return slices.Contains(DefaultSections, name)
+}
+
+// Sections returns the sections the page, the PDF and the document exports
+// render, in order: layout.sections, or all of them in the default order.
+// Sections left out of layout.sections are hidden. Renderers still skip an
+// empty section. The default order is a copy, safe to modify.
+func Sections(data ResumeData) []string {
+	if len(data.Layout.Sections) == 0 {
+		return slices.Clone(DefaultSections)
+	}
+	return data.Layout.Sections
+}
+
+// CertificationDates describes when a certification was issued and until
+// when it is valid, such as "Mar 2023, expires Mar 2026"
+func CertificationDates(cert CertificationItem) string {
+	switch {
+	case cert.Expires == "":
+		return cert.Date
+	case cert.Date == "":
+		return "Expires " + cert.Expires
+	}
+	return cert.Date + ", expires " + cert.Expires
+}
+
+// Period describes a date range such as "Mar 2022 - Present", or the one date
+// that is set
+func Period(start, end string) string {
+	if start == "" || end == "" {
+		return start + end
+	}
+	return start + " - " + end
+}
+
+// ContributionSummary describes the role in an open-source project and the
+// number of merged pull requests, such as "Maintainer, 42 pull requests"
+func ContributionSummary(contribution ContributionItem) string {
+	var parts []string
+	if contribution.Role != "" {
+		parts = append(parts, contribution.Role)
+	}
+	switch n := contribution.PullRequests; {
+	case n == 1:
+		parts = append(parts, "1 pull request")
+	case n > 1:
+		parts = append(parts, strconv.Itoa(n)+" pull requests")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// TalkVenue describes where a talk was given, such as "GopherCon, Berlin"
+func TalkVenue(talk TalkItem) string {
+	if talk.Event == "" || talk.Location == "" {
+		return talk.Event + talk.Location
+	}
+	return talk.Event + ", " + talk.Location
+}
@@ -0,0 +1,185 @@
+package components
+
+import (
+	"cmp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxMetaDescriptionLength is the length after which search engines truncate descriptions
+	MaxMetaDescriptionLength = 160
+
+	// OGImagePath is where the server and the static build publish the preview card
+	OGImagePath = "/og.png"
+)
+
+// PersonLD is the schema.org Person embedded into the page as JSON-LD
+type PersonLD struct {
+	Context       string           `json:"@context"`
+	Type          string           `json:"@type"`
+	Name          string           `json:"name"`
+	JobTitle      string           `json:"jobTitle,omitempty"`
+	Description   string           `json:"description,omitempty"`
+	Email         string           `json:"email,omitempty"`
+	Telephone     string           `json:"telephone,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	URL           string           `json:"url,omitempty"`
+	Address       *PostalAddressLD `json:"address,omitempty"`
+	WorksFor      []OrganizationLD `json:"worksFor,omitempty"`
+	AlumniOf      []OrganizationLD `json:"alumniOf,omitempty"`
+	KnowsAbout    []string         `json:"knowsAbout,omitempty"`
+	KnowsLanguage []string         `json:"knowsLanguage,omitempty"`
+	SameAs        []string         `json:"sameAs,omitempty"`
+}
+
+// PostalAddressLD is a schema.org PostalAddress
+type PostalAddressLD struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality,omitempty"`
+	AddressCountry  string `json:"addressCountry,omitempty"`
+}
+
+// OrganizationLD is a schema.org Organization or EducationalOrganization
+type OrganizationLD struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// personJSONLD builds the schema.org Person describing the resume owner
+func personJSONLD(data ResumeData) PersonLD {
+	p := data.Personal
+	person := PersonLD{
+		Context:     "https://schema.org",
+		Type:        "Person",
+		Name:        p.Name,
+		JobTitle:    p.Title,
+		Description: p.Summary,
+		Telephone:   p.Phone,
+		Image:       PhotoURL(data),
+		URL:         p.Website,
+	}
+
+	if p.Email != "" {
+		person.Email = "mailto:" + p.Email
+	}
+
+	if p.Location != "" {
+		locality, country, found := strings.Cut(p.Location, ", ")
+		if !found {
+			locality, country = "", p.Location
+		}
+		person.Address = &PostalAddressLD{Type: "PostalAddress", AddressLocality: locality, AddressCountry: country}
+	}
+
+	// Only current positions count as employers
+	for _, exp := range data.Experience {
+		if strings.EqualFold(exp.EndDate, "Present") || exp.EndDate == "" {
+			person.WorksFor = append(person.WorksFor, OrganizationLD{Type: "Organization", Name: exp.Company})
+		}
+	}
+
+	for _, edu := range data.Education {
+		person.AlumniOf = append(person.AlumniOf, OrganizationLD{Type: "EducationalOrganization", Name: edu.Institution})
+	}
+
+	for _, category := range data.Skills {
+		for _, item := range category.Items {
+			person.KnowsAbout = append(person.KnowsAbout, item.Name)
+		}
+	}
+
+	for _, lang := range data.Languages {
+		person.KnowsLanguage = append(person.KnowsLanguage, lang.Language)
+	}
+
+	for _, profile := range []string{p.Linkedin, p.Github} {
+		if profile != "" {
+			person.SameAs = append(person.SameAs, profile)
+		}
+	}
+
+	return person
+}
+
+// metaDescription shortens the summary to MaxMetaDescriptionLength runes,
+// cutting at a word boundary
+func metaDescription(summary string) string {
+	summary = strings.Join(strings.Fields(summary), " ")
+	if utf8.RuneCountInString(summary) <= MaxMetaDescriptionLength {
+		return summary
+	}
+
+	runes := []rune(summary)
+	cut := string(runes[:MaxMetaDescriptionLength-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, ",.;:") + "…"
+}
+
+// pageTitle is the document title of the resume page
+func pageTitle(data ResumeData) string {
+	return data.Personal.Name + " - Resume"
+}
+
+// ogImageURL is the preview card URL, absolute when site.baseURL or
+// personal.website is set because most link preview crawlers ignore
+// relative image URLs
+func ogImageURL(data ResumeData) string {
+	return strings.TrimRight(cmp.Or(data.Site.BaseURL, data.Personal.Website), "/") + OGImagePath
+}
+
+// BaseURL is the address of the published site without a trailing slash,
+// empty when site.baseURL is not set
+func BaseURL(data ResumeData) string {
+	return strings.TrimRight(data.Site.BaseURL, "/")
+}
+
+// CanonicalURL is the address search engines should index the page under,
+// empty without a base URL
+func CanonicalURL(data ResumeData) string {
+	base := BaseURL(data)
+	if base == "" {
+		return ""
+	}
+	return base + cmp.Or(data.Page.Path, "/")
+}
+
+// PhotoURL is the absolute address of personal.photo. A site-relative photo
+// such as /static/img/me.jpg is resolved against the site like the preview
+// image, and is empty when neither site.baseURL nor personal.website is set.
+func PhotoURL(data ResumeData) string {
+	photo := data.Personal.Photo
+	if photo == "" || strings.Contains(photo, "://") {
+		return photo
+	}
+	base := strings.TrimRight(cmp.Or(data.Site.BaseURL, data.Personal.Website), "/")
+	if base == "" {
+		return ""
+	}
+	return base + "/" + strings.TrimLeft(photo, "/")
+}
+
+// SiteURL is the address the resume is shared under: the canonical URL when
+// site.baseURL is set, personal.website otherwise
+func SiteURL(data ResumeData) string {
+	return cmp.Or(CanonicalURL(data), data.Personal.Website)
+}
+
+// firstName and lastName split the name for the Open Graph profile tags
+func firstName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return name
+	}
+	return strings.Join(fields[:len(fields)-1], " ")
+}
+
+func lastName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
@@ -0,0 +1,118 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPersonJSONLD(t *testing.T) {
+	var data ResumeData
+	data.Personal = PersonalInfo{
+		Name:     "Jane Doe",
+		Title:    "Golang Developer",
+		Email:    "jane@example.com",
+		Location: "Kyiv, Ukraine",
+		Linkedin: "https://www.linkedin.com/in/jane",
+		Github:   "https://github.com/jane",
+		Photo:    "/static/img/jane.jpg",
+	}
+	data.Experience = []ExperienceItem{
+		{Company: "ACME", EndDate: "Present"},
+		{Company: "Initech", EndDate: "Jul 2022"},
+	}
+	data.Education = []EducationItem{{Institution: "KPI"}}
+	data.Skills = []SkillCategory{{Category: "Languages", Items: []Link{{Name: "Go"}, {Name: "SQL"}}}}
+
+	person := personJSONLD(data)
+
+	if person.Type != "Person" || person.JobTitle != "Golang Developer" || person.Email != "mailto:jane@example.com" {
+		t.Errorf("unexpected person %+v", person)
+	}
+	if want := []OrganizationLD{{Type: "Organization", Name: "ACME"}}; !reflect.DeepEqual(person.WorksFor, want) {
+		t.Errorf("WorksFor = %+v, want %+v", person.WorksFor, want)
+	}
+	if want := []OrganizationLD{{Type: "EducationalOrganization", Name: "KPI"}}; !reflect.DeepEqual(person.AlumniOf, want) {
+		t.Errorf("AlumniOf = %+v, want %+v", person.AlumniOf, want)
+	}
+	if person.Image != "" {
+		t.Errorf("Image = %q, want no relative photo without a site address", person.Image)
+	}
+	data.Site.BaseURL = "https://cv.example.com"
+	if got := personJSONLD(data).Image; got != "https://cv.example.com/static/img/jane.jpg" {
+		t.Errorf("Image = %q, want the photo on the base URL", got)
+	}
+	if want := []string{"Go", "SQL"}; !reflect.DeepEqual(person.KnowsAbout, want) {
+		t.Errorf("KnowsAbout = %v, want %v", person.KnowsAbout, want)
+	}
+	if want := []string{data.Personal.Linkedin, data.Personal.Github}; !reflect.DeepEqual(person.SameAs, want) {
+		t.Errorf("SameAs = %v, want %v", person.SameAs, want)
+	}
+	if person.Address == nil || person.Address.AddressLocality != "Kyiv" || person.Address.AddressCountry != "Ukraine" {
+		t.Errorf("Address = %+v", person.Address)
+	}
+}
+
+func TestMetaDescription(t *testing.T) {
+	if got := metaDescription("  Short\n summary. "); got != "Short summary." {
+		t.Errorf("metaDescription() = %q", got)
+	}
+
+	long := strings.Repeat("word ", 100)
+	got := metaDescription(long)
+	if utf8.RuneCountInString(got) > MaxMetaDescriptionLength {
+		t.Errorf("description is %d runes long, want at most %d", utf8.RuneCountInString(got), MaxMetaDescriptionLength)
+	}
+	if !strings.HasSuffix(got, "word…") {
+		t.Errorf("description should be cut at a word boundary, got %q", got)
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	var data ResumeData
+	if got := CanonicalURL(data); got != "" {
+		t.Errorf("CanonicalURL() without a base URL = %q, want none", got)
+	}
+
+	data.Site.BaseURL = "https://cv.example.com/"
+	if got := CanonicalURL(data); got != "https://cv.example.com/" {
+		t.Errorf("CanonicalURL() of the root = %q", got)
+	}
+	data.Page.Path = "/uk/backend/"
+	if got := CanonicalURL(data); got != "https://cv.example.com/uk/backend/" {
+		t.Errorf("CanonicalURL() of /uk/backend/ = %q", got)
+	}
+	if got := ogImageURL(data); got != "https://cv.example.com/og.png" {
+		t.Errorf("ogImageURL() = %q, want it on the base URL", got)
+	}
+}
+
+func TestPhotoURL(t *testing.T) {
+	var data ResumeData
+	data.Personal.Photo = "/static/img/jane.jpg"
+	if got := PhotoURL(data); got != "" {
+		t.Errorf("PhotoURL() without a site address = %q, want empty", got)
+	}
+	data.Personal.Website = "https://jane.example.com"
+	data.Site.BaseURL = "https://cv.example.com/"
+	if got := PhotoURL(data); got != "https://cv.example.com/static/img/jane.jpg" {
+		t.Errorf("PhotoURL() = %q, want it on the base URL", got)
+	}
+	data.Personal.Photo = "https://example.com/jane.jpg"
+	if got := PhotoURL(data); got != data.Personal.Photo {
+		t.Errorf("PhotoURL() of an absolute URL = %q, want it unchanged", got)
+	}
+}
+
+func TestSiteURL(t *testing.T) {
+	var data ResumeData
+	data.Personal.Website = "https://jane.example.com"
+	if got := SiteURL(data); got != "https://jane.example.com" {
+		t.Errorf("SiteURL() without a base URL = %q, want personal.website", got)
+	}
+	data.Site.BaseURL = "https://cv.example.com"
+	if got := SiteURL(data); got != CanonicalURL(data) {
+		t.Errorf("SiteURL() = %q, want the canonical URL %q", got, CanonicalURL(data))
+	}
+}
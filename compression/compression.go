@@ -0,0 +1,196 @@
+// Package compression negotiates the content encoding of responses and
+// compresses them with brotli or gzip, on the fly or ahead of time.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// The supported content encodings
+const (
+	Brotli = "br"
+	Gzip   = "gzip"
+)
+
+// Encodings are the supported encodings, the preferred one first
+var Encodings = []string{Brotli, Gzip}
+
+// minSize is the smallest body worth compressing; below it the headers of
+// the encoding outweigh the saving
+const minSize = 512
+
+// Extension is the suffix of the precompressed sibling of a file, such as
+// index.html.gz, that static hosts serve for encoding
+func Extension(encoding string) string {
+	if encoding == Gzip {
+		return ".gz"
+	}
+	return "." + encoding
+}
+
+// Compressible reports whether content of the media type is worth
+// compressing: text, documents and SVG. Other images are compressed
+// already, and the fonts would take too long to compress at start-up.
+func Compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml",
+		"application/pdf", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// Compress returns data compressed with encoding at the best level, for
+// files that are compressed once and served many times
+func Compress(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case Brotli:
+		w = brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	case Gzip:
+		w, _ = gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Negotiate returns the encoding among available that the request accepts
+// with the highest weight, preferring the earlier ones on a tie, or "" when
+// the response should not be encoded
+func Negotiate(r *http.Request, available ...string) string {
+	weights := map[string]float64{}
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if name == "" {
+			continue
+		}
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = v
+			}
+		}
+		weights[strings.ToLower(name)] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range available {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+// Handler compresses the responses of next that are compressible and not
+// encoded already. Small bodies, partial content and HEAD requests are
+// sent as they are.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := ""
+		if r.Method != http.MethodHead {
+			encoding = Negotiate(r, Encodings...)
+		}
+		cw := &writer{ResponseWriter: w, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// writer encodes the body once the headers show that it is worth it
+type writer struct {
+	http.ResponseWriter
+	encoding string
+	// decided is set once the headers are written
+	decided bool
+	enc     io.WriteCloser
+}
+
+func (w *writer) WriteHeader(status int) {
+	if !w.decided {
+		w.decide(status)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decide sets up the encoder when the response is compressible
+func (w *writer) decide(status int) {
+	w.decided = true
+	h := w.Header()
+	if !Compressible(h.Get("Content-Type")) || h.Get("Content-Encoding") != "" {
+		return
+	}
+	// The body depends on Accept-Encoding, so caches keep one per encoding
+	h.Add("Vary", "Accept-Encoding")
+	if w.encoding == "" || status < http.StatusOK || status == http.StatusNoContent ||
+		status == http.StatusNotModified || status == http.StatusPartialContent {
+		return
+	}
+	if size, err := strconv.Atoi(h.Get("Content-Length")); err == nil && size < minSize {
+		return
+	}
+
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", w.encoding)
+	if w.encoding == Brotli {
+		w.enc = brotli.NewWriterLevel(w.ResponseWriter, brotli.DefaultCompression)
+	} else {
+		w.enc = gzip.NewWriter(w.ResponseWriter)
+	}
+}
+
+// close flushes the rest of the encoded body
+func (w *writer) close() {
+	if w.enc != nil {
+		w.enc.Close()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
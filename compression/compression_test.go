@@ -0,0 +1,109 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiate(t *testing.T) {
+	for accept, want := range map[string]string{
+		"":                        "",
+		"gzip, deflate, br, zstd": Brotli,
+		"gzip":                    Gzip,
+		"br;q=0.5, gzip":          Gzip,
+		"br;q=0, gzip;q=0":        "",
+		"*":                       Brotli,
+		"identity":                "",
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", accept)
+		if got := Negotiate(r, Encodings...); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", accept, got, want)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	data := []byte(strings.Repeat("<p>resume</p>", 100))
+	for _, encoding := range Encodings {
+		compressed, err := Compress(encoding, data)
+		if err != nil {
+			t.Fatalf("Compress(%s) error = %v", encoding, err)
+		}
+		if got := decode(t, encoding, compressed); !bytes.Equal(got, data) {
+			t.Errorf("Compress(%s) does not decode to the data", encoding)
+		}
+	}
+	if _, err := Compress("zstd", data); err == nil {
+		t.Error("Compress(zstd) error = nil")
+	}
+}
+
+func decode(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var r io.Reader = brotli.NewReader(bytes.NewReader(data))
+	if encoding == Gzip {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = zr
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", encoding, err)
+	}
+	return out
+}
+
+func TestHandler(t *testing.T) {
+	page := strings.Repeat("<p>resume</p>", 100)
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, page)
+		case "/small":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", "2")
+			io.WriteString(w, "{}")
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			io.WriteString(w, page)
+		}
+	}))
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Accept-Encoding", accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	for _, encoding := range Encodings {
+		rec := get("/page", encoding)
+		if got := rec.Header().Get("Content-Encoding"); got != encoding {
+			t.Errorf("page Content-Encoding = %q, want %q", got, encoding)
+			continue
+		}
+		if got := decode(t, encoding, rec.Body.Bytes()); string(got) != page {
+			t.Errorf("page in %s does not decode to the page", encoding)
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("page Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+		}
+	}
+
+	for path, accept := range map[string]string{"/page": "", "/small": "br", "/image": "br"} {
+		if rec := get(path, accept); rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("%s with Accept-Encoding %q was encoded in %s", path, accept, rec.Header().Get("Content-Encoding"))
+		}
+	}
+}
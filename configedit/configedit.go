@@ -0,0 +1,437 @@
+// Package configedit changes the lists of config.yaml the way a person
+// editing the file would: it edits the yaml.v3 node tree in place, so
+// comments, key order and quoting survive, and it keeps every replaced
+// version of the file in a revision history.
+package configedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"gopkg.in/yaml.v3"
+)
+
+// Field kinds, deciding how a form value maps to YAML
+const (
+	// KindText is a single string
+	KindText = "text"
+	// KindLines is a list of strings, one per line
+	KindLines = "lines"
+	// KindLinks is a list of name/link pairs, one "Name | URL" per line
+	KindLinks = "links"
+	// KindTags is a comma separated list written as a flow sequence
+	KindTags = "tags"
+)
+
+// Field is an editable key of a list item
+type Field struct {
+	Key   string
+	Label string
+	Kind  string
+}
+
+// Section is an editable list of config.yaml. The first field names the
+// item in listings.
+type Section struct {
+	Key    string
+	Title  string
+	Fields []Field
+}
+
+var variantsField = Field{"variants", "Variants", KindTags}
+
+// Sections are the lists the editor can change
+var Sections = []Section{
+	{"experience", "Experience", []Field{
+		{"position", "Position", KindText},
+		{"company", "Company", KindText},
+		{"location", "Location", KindText},
+		{"employmentType", "Employment type", KindText},
+		{"startDate", "Start date", KindText},
+		{"endDate", "End date", KindText},
+		{"description", "Description", KindLines},
+		{"technologies", "Technologies", KindLinks},
+		variantsField,
+	}},
+	{"skills", "Skills", []Field{
+		{"category", "Category", KindText},
+		{"items", "Skills", KindLinks},
+		variantsField,
+	}},
+	{"projects", "Projects", []Field{
+		{"name", "Name", KindText},
+		{"description", "Description", KindText},
+		{"link", "Link", KindText},
+		{"technologies", "Technologies", KindLinks},
+		variantsField,
+	}},
+	{"languages", "Languages", []Field{
+		{"language", "Language", KindText},
+		{"proficiency", "Proficiency", KindText},
+	}},
+}
+
+// FindSection returns the section with the given key
+func FindSection(key string) (Section, bool) {
+	for _, section := range Sections {
+		if section.Key == key {
+			return section, true
+		}
+	}
+	return Section{}, false
+}
+
+// Values are the form values of an item by field key
+type Values map[string]string
+
+// Document is a parsed config file
+type Document struct {
+	original []byte
+	root     yaml.Node
+}
+
+// Parse reads a config file
+func Parse(data []byte) (*Document, error) {
+	d := &Document{original: data}
+	if err := yaml.Unmarshal(data, &d.root); err != nil {
+		return nil, fmt.Errorf("error parsing config: %w", err)
+	}
+	if len(d.root.Content) == 0 || d.root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("config is not a YAML mapping")
+	}
+	return d, nil
+}
+
+// Encode writes the document back with the two-space indentation and the
+// blank lines of the original file
+func (d *Document) Encode() ([]byte, error) {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&d.root); err != nil {
+		return nil, fmt.Errorf("error encoding config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return restoreBlankLines(d.original, b.Bytes()), nil
+}
+
+// Resume decodes the document as it is now
+func (d *Document) Resume() (components.ResumeData, error) {
+	var data components.ResumeData
+	if err := d.root.Decode(&data); err != nil {
+		return data, fmt.Errorf("error decoding config: %w", err)
+	}
+	return data, nil
+}
+
+// Len is the number of items in the section
+func (d *Document) Len(section string) int {
+	if list := d.list(section, false); list != nil {
+		return len(list.Content)
+	}
+	return 0
+}
+
+// Values returns the form values of item i of section
+func (d *Document) Values(section Section, i int) (Values, error) {
+	item, err := d.item(section.Key, i)
+	if err != nil {
+		return nil, err
+	}
+
+	values := Values{}
+	for _, field := range section.Fields {
+		values[field.Key] = readField(lookup(item, field.Key), field.Kind)
+	}
+	return values, nil
+}
+
+// Set writes values into item i of section, or appends a new item when i
+// is the length of the section
+func (d *Document) Set(section Section, i int, values Values) error {
+	list := d.list(section.Key, true)
+	if i == len(list.Content) {
+		list.Content = append(list.Content, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+	item, err := d.item(section.Key, i)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range section.Fields {
+		writeField(item, field, values[field.Key])
+	}
+	return nil
+}
+
+// Move moves item i of section to position j
+func (d *Document) Move(section string, i, j int) error {
+	item, err := d.item(section, i)
+	if err != nil {
+		return err
+	}
+	list := d.list(section, false)
+	if j < 0 || j >= len(list.Content) {
+		return fmt.Errorf("%s has no position %d", section, j)
+	}
+
+	list.Content = append(list.Content[:i], list.Content[i+1:]...)
+	list.Content = append(list.Content[:j], append([]*yaml.Node{item}, list.Content[j:]...)...)
+	return nil
+}
+
+// Delete removes item i of section
+func (d *Document) Delete(section string, i int) error {
+	if _, err := d.item(section, i); err != nil {
+		return err
+	}
+	list := d.list(section, false)
+	list.Content = append(list.Content[:i], list.Content[i+1:]...)
+	return nil
+}
+
+// list returns the sequence under key, adding an empty one when create is set
+func (d *Document) list(key string, create bool) *yaml.Node {
+	doc := d.root.Content[0]
+	node := lookup(doc, key)
+	if node == nil {
+		if !create {
+			return nil
+		}
+		node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node)
+	}
+	if node.Kind != yaml.SequenceNode {
+		if !create {
+			return nil
+		}
+		// An empty key such as "projects:" decodes as null
+		node.Kind, node.Tag, node.Value, node.Style = yaml.SequenceNode, "!!seq", "", 0
+	}
+	return node
+}
+
+func (d *Document) item(section string, i int) (*yaml.Node, error) {
+	list := d.list(section, false)
+	if list == nil || i < 0 || i >= len(list.Content) {
+		return nil, fmt.Errorf("%s has no item %d", section, i)
+	}
+	item := list.Content[i]
+	if item.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s[%d] is not a mapping", section, i)
+	}
+	return item, nil
+}
+
+// lookup returns the value of key in a mapping node
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// remove deletes key from a mapping node
+func remove(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func readField(node *yaml.Node, kind string) string {
+	if node == nil {
+		return ""
+	}
+
+	switch kind {
+	case KindText:
+		return node.Value
+	case KindLines, KindTags:
+		var values []string
+		for _, item := range node.Content {
+			values = append(values, item.Value)
+		}
+		if kind == KindTags {
+			return strings.Join(values, ", ")
+		}
+		return strings.Join(values, "\n")
+	case KindLinks:
+		var lines []string
+		for _, item := range node.Content {
+			line := readField(lookup(item, "name"), KindText)
+			if link := readField(lookup(item, "link"), KindText); link != "" {
+				line += " | " + link
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
+
+// writeField stores a form value under field.Key, reusing the existing node
+// so that its comments and quoting stay
+func writeField(item *yaml.Node, field Field, value string) {
+	value = strings.TrimSpace(value)
+	node := lookup(item, field.Key)
+
+	var content []*yaml.Node
+	switch field.Kind {
+	case KindText:
+		if node == nil {
+			if value == "" {
+				return
+			}
+			node = appendKey(item, field.Key)
+		}
+		setScalar(node, value)
+		return
+	case KindLines:
+		for _, line := range splitList(value, "\n") {
+			content = append(content, newScalar(line, node))
+		}
+	case KindTags:
+		for _, tag := range splitList(value, ",") {
+			content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: tag})
+		}
+	case KindLinks:
+		for _, line := range splitList(value, "\n") {
+			name, link, _ := strings.Cut(line, "|")
+			pair := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setScalar(appendKey(pair, "name"), strings.TrimSpace(name))
+			if link = strings.TrimSpace(link); link != "" {
+				setScalar(appendKey(pair, "link"), link)
+			}
+			content = append(content, pair)
+		}
+	}
+
+	// Empty variants are left out, like the omitempty tag does
+	if len(content) == 0 && (node == nil || field.Kind == KindTags) {
+		remove(item, field.Key)
+		return
+	}
+	if node == nil {
+		node = appendKey(item, field.Key)
+	}
+	node.Kind, node.Tag, node.Value, node.Content = yaml.SequenceNode, "!!seq", "", content
+	if field.Kind == KindTags {
+		node.Style = yaml.FlowStyle
+	}
+}
+
+func appendKey(mapping *yaml.Node, key string) *yaml.Node {
+	value := &yaml.Node{}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// setScalar sets a string, quoting it like the rest of config.yaml unless
+// the node already has a style
+func setScalar(node *yaml.Node, value string) {
+	node.Kind, node.Tag, node.Value, node.Content = yaml.ScalarNode, "!!str", value, nil
+	if node.Style == 0 {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+}
+
+// newScalar creates a list item in the style of the existing items of list
+func newScalar(value string, list *yaml.Node) *yaml.Node {
+	node := &yaml.Node{}
+	if list != nil && len(list.Content) > 0 {
+		node.Style = list.Content[0].Style
+	}
+	setScalar(node, value)
+	return node
+}
+
+func splitList(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// restoreBlankLines puts back the blank lines that the YAML encoder drops.
+// Every line of out that also appears in original, in the same order, gets
+// the blank line that preceded it there; new top-level keys get one too.
+// The encoder keeps some blank lines itself, such as the ones between the
+// comment groups before a key; they are dropped first, so that the original
+// alone decides where blank lines go.
+func restoreBlankLines(original, out []byte) []byte {
+	var before []bool
+	var orig []string
+	blank := false
+	for _, line := range strings.Split(string(original), "\n") {
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			continue
+		}
+		orig = append(orig, line)
+		before = append(before, blank)
+		blank = false
+	}
+	var lines []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	// Longest common subsequence of the non-blank lines
+	lcs := make([][]int, len(lines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(orig)+1)
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		for j := len(orig) - 1; j >= 0; j-- {
+			if lines[i] == orig[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(lines) {
+		line := lines[i]
+		switch {
+		case j < len(orig) && line == orig[j]:
+			if before[j] && i > 0 {
+				b.WriteString("\n")
+			}
+			j++
+			i++
+		case j < len(orig) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+			continue
+		default:
+			if i > 0 && isTopLevelKey(line) && !strings.HasPrefix(lines[i-1], "#") {
+				b.WriteString("\n")
+			}
+			i++
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+func isTopLevelKey(line string) bool {
+	return line != "" && line[0] != ' ' && line[0] != '-' && line[0] != '#'
+}
@@ -0,0 +1,187 @@
+package configedit
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const config = `personal:
+  name: "Jane Doe" # shown in the header
+  title: "Developer"
+
+experience:
+  # Current job
+  - company: "ACME"
+    position: "Go Developer"
+    startDate: "Mar 2022"
+    endDate: "Present"
+    description:
+      - "Built things"
+    technologies:
+      - name: "Go"
+        link: "https://go.dev/"
+
+  - company: "Studio"
+    position: "Game Designer"
+    startDate: "2018"
+    endDate: "Feb 2022"
+    variants: [gamedev]
+
+languages:
+  - language: "English"
+    proficiency: "Fluent"
+`
+
+func parse(t *testing.T) *Document {
+	t.Helper()
+	doc, err := Parse([]byte(config))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return doc
+}
+
+func encode(t *testing.T, doc *Document) string {
+	t.Helper()
+	out, err := doc.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return string(out)
+}
+
+func TestRoundTrip(t *testing.T) {
+	if got := encode(t, parse(t)); got != config {
+		t.Errorf("Encode() changed an untouched config:\n%s", got)
+	}
+}
+
+// TestRoundTripConfig saves the config of the repository without changes,
+// which must leave every byte of it in place
+func TestRoundTripConfig(t *testing.T) {
+	data, err := os.ReadFile("../config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, err := doc.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("Encode() changed config.yaml:\n%s", got)
+	}
+}
+
+func TestValues(t *testing.T) {
+	experience, _ := FindSection("experience")
+	values, err := parse(t).Values(experience, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["company"] != "ACME" || values["description"] != "Built things" || values["technologies"] != "Go | https://go.dev/" {
+		t.Errorf("Values() = %v", values)
+	}
+
+	values, _ = parse(t).Values(experience, 1)
+	if values["variants"] != "gamedev" {
+		t.Errorf("variants = %q, want gamedev", values["variants"])
+	}
+}
+
+func TestSet(t *testing.T) {
+	doc := parse(t)
+	experience, _ := FindSection("experience")
+	values, _ := doc.Values(experience, 0)
+	values["position"] = "Senior Go Developer"
+	values["description"] = "Built things\nLed a team"
+	values["technologies"] = "Go | https://go.dev/\nPostgreSQL"
+	values["variants"] = "backend, gamedev"
+	if err := doc.Set(experience, 0, values); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got := encode(t, doc)
+	for _, want := range []string{
+		`  name: "Jane Doe" # shown in the header`,
+		"  # Current job\n  - company: \"ACME\"\n    position: \"Senior Go Developer\"",
+		"      - \"Built things\"\n      - \"Led a team\"",
+		"      - name: \"PostgreSQL\"\n",
+		"    variants: [backend, gamedev]\n\n  - company: \"Studio\"",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("config is missing %q:\n%s", want, got)
+		}
+	}
+
+	data, err := doc.Resume()
+	if err != nil || data.Experience[0].Position != "Senior Go Developer" || len(data.Experience[0].Technologies) != 2 {
+		t.Errorf("Resume() = %+v, %v", data.Experience, err)
+	}
+}
+
+func TestAddMoveDelete(t *testing.T) {
+	doc := parse(t)
+	projects, _ := FindSection("projects")
+	if err := doc.Set(projects, doc.Len("projects"), Values{"name": "CV", "link": "https://example.com"}); err != nil {
+		t.Fatalf("Set() of a new item error = %v", err)
+	}
+	if doc.Len("projects") != 1 {
+		t.Errorf("Len(projects) = %d, want 1", doc.Len("projects"))
+	}
+
+	if err := doc.Move("experience", 1, 0); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if err := doc.Move("experience", 0, 2); err == nil {
+		t.Error("Move() past the end succeeded")
+	}
+	if err := doc.Delete("languages", 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	data, err := doc.Resume()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Experience[0].Company != "Studio" || len(data.Languages) != 0 || data.Projects[0].Link != "https://example.com" {
+		t.Errorf("Resume() = %+v", data)
+	}
+	got := encode(t, doc)
+	if !strings.Contains(got, "\n\nprojects:\n  - name: \"CV\"") {
+		t.Errorf("the new section is not separated like the others:\n%s", got)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	history := NewHistory(t.TempDir())
+	if revisions, err := history.List(); err != nil || len(revisions) != 0 {
+		t.Fatalf("List() of an empty history = %v, %v", revisions, err)
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first, err := history.Save([]byte("first"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := history.Save([]byte("second"), now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := history.List()
+	if err != nil || len(revisions) != 2 || !revisions[1].Time.Equal(now) {
+		t.Fatalf("List() = %+v, %v, want the two revisions, newest first", revisions, err)
+	}
+	if data, err := history.Read(first.Name); err != nil || string(data) != "first" {
+		t.Errorf("Read() = %q, %v", data, err)
+	}
+	if _, err := history.Read("../config.yaml"); !errors.Is(err, ErrNoRevision) {
+		t.Errorf("Read() outside the history error = %v, want ErrNoRevision", err)
+	}
+}
@@ -0,0 +1,119 @@
+package configedit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// revisionLayout names the revision files; the names sort by time
+const revisionLayout = "20060102T150405.000000000Z"
+
+var revisionName = regexp.MustCompile(`^\d{8}T\d{6}\.\d{9}Z\.yaml$`)
+
+// ErrNoRevision is returned for names that are not revisions in the history
+var ErrNoRevision = errors.New("no such revision")
+
+// Revision is a replaced version of the config
+type Revision struct {
+	Name string
+	Time time.Time
+	Size int64
+}
+
+// History keeps replaced versions of the config in a directory
+type History struct {
+	dir string
+}
+
+// NewHistory stores the revisions in dir, created with the first one
+func NewHistory(dir string) *History {
+	return &History{dir: dir}
+}
+
+// Save stores data as the revision replaced at now
+func (h *History) Save(data []byte, now time.Time) (Revision, error) {
+	if err := os.MkdirAll(h.dir, 0700); err != nil {
+		return Revision{}, fmt.Errorf("error creating revision history: %w", err)
+	}
+	now = now.UTC()
+	name := now.Format(revisionLayout) + ".yaml"
+	if err := os.WriteFile(filepath.Join(h.dir, name), data, 0600); err != nil {
+		return Revision{}, fmt.Errorf("error saving revision: %w", err)
+	}
+	return Revision{Name: name, Time: now, Size: int64(len(data))}, nil
+}
+
+// List returns the revisions, newest first
+func (h *History) List() ([]Revision, error) {
+	entries, err := os.ReadDir(h.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading revision history: %w", err)
+	}
+
+	var revisions []Revision
+	for _, entry := range entries {
+		if !revisionName.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		t, _ := time.Parse(revisionLayout, strings.TrimSuffix(entry.Name(), ".yaml"))
+		revisions = append(revisions, Revision{Name: entry.Name(), Time: t, Size: info.Size()})
+	}
+	slices.Reverse(revisions)
+	return revisions, nil
+}
+
+// Read returns the content of the named revision
+func (h *History) Read(name string) ([]byte, error) {
+	// The name comes from a URL; only revision file names are accepted
+	if !revisionName.MatchString(name) {
+		return nil, ErrNoRevision
+	}
+	data, err := os.ReadFile(filepath.Join(h.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoRevision
+	}
+	return data, err
+}
+
+// WriteFile replaces the config at path through a rename so that readers
+// never see half of it. The file keeps its permissions.
+func WriteFile(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+// Package contact delivers the messages of the contact form: it validates
+// them, sends them through an SMTP server and keeps the ones that could not
+// be delivered in a local outbox file.
+package contact
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of the form fields
+const (
+	MaxNameLength    = 200
+	MaxMessageLength = 5000
+)
+
+// Message is a message sent through the contact form
+type Message struct {
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Text     string    `json:"text"`
+	Received time.Time `json:"received"`
+}
+
+// Validate reports every problem with the message at once
+func (m Message) Validate() error {
+	var errs []error
+	switch {
+	case strings.TrimSpace(m.Name) == "":
+		errs = append(errs, errors.New("name is required"))
+	case utf8.RuneCountInString(m.Name) > MaxNameLength:
+		errs = append(errs, fmt.Errorf("name is longer than %d characters", MaxNameLength))
+	case strings.ContainsAny(m.Name, "\r\n"):
+		errs = append(errs, errors.New("name must be a single line"))
+	}
+
+	if address, err := mail.ParseAddress(m.Email); err != nil || address.Address != m.Email {
+		errs = append(errs, errors.New("email is not a valid address"))
+	}
+
+	switch {
+	case strings.TrimSpace(m.Text) == "":
+		errs = append(errs, errors.New("message is required"))
+	case utf8.RuneCountInString(m.Text) > MaxMessageLength:
+		errs = append(errs, fmt.Errorf("message is longer than %d characters", MaxMessageLength))
+	}
+	return errors.Join(errs...)
+}
+
+// Mailer delivers messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer sends messages to To through the SMTP server at Addr. The
+// connection is upgraded with STARTTLS when the server offers it, and
+// authenticated when Username is set.
+type SMTPMailer struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       string
+	Timeout  time.Duration
+}
+
+// Send delivers msg with the visitor's address as Reply-To
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to SMTP server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address %q: %w", m.Addr, err)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("error starting SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return fmt.Errorf("error authenticating to SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.From); err != nil {
+		return fmt.Errorf("SMTP server refused sender: %w", err)
+	}
+	if err := client.Rcpt(m.To); err != nil {
+		return fmt.Errorf("SMTP server refused recipient: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP server refused message: %w", err)
+	}
+	if _, err := w.Write(m.compose(msg)); err != nil {
+		return fmt.Errorf("error sending message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server refused message: %w", err)
+	}
+	return client.Quit()
+}
+
+// compose formats msg as an email. The name is encoded, so it cannot inject
+// headers; Validate has already rejected line breaks in it.
+func (m *SMTPMailer) compose(msg Message) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, value)
+	}
+	header("From", m.From)
+	header("To", m.To)
+	header("Reply-To", (&mail.Address{Name: msg.Name, Address: msg.Email}).String())
+	header("Subject", mime.QEncoding.Encode("utf-8", "CV contact from "+msg.Name))
+	header("Date", msg.Received.Format(time.RFC1123Z))
+	header("Message-ID", messageID(m.From))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&b)
+	fmt.Fprintf(body, "%s <%s> wrote:\r\n\r\n%s\r\n", msg.Name, msg.Email, strings.ReplaceAll(msg.Text, "\n", "\r\n"))
+	body.Close()
+	return b.Bytes()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok {
+		domain = strings.TrimSuffix(d, ">")
+	}
+	id := make([]byte, 12)
+	_, _ = io.ReadFull(rand.Reader, id)
+	return fmt.Sprintf("<%x@%s>", id, domain)
+}
+
+// Outbox keeps undelivered messages in a JSON Lines file, to be read by hand
+// or resent later
+type Outbox struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewOutbox appends to the file at path, creating it with the first message
+func NewOutbox(path string) *Outbox {
+	return &Outbox{path: path}
+}
+
+// Save appends msg to the outbox
+func (o *Outbox) Save(msg Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	file, err := os.OpenFile(o.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening contact outbox: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing contact outbox: %w", err)
+	}
+	return file.Close()
+}
+
+// Limiter allows a number of requests per key, an IP address, within a
+// sliding window
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string][]time.Time
+}
+
+// NewLimiter allows limit requests per key in every window
+func NewLimiter(limit int, window time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, seen: map[string][]time.Time{}}
+}
+
+// Allow records a request of key at now and reports whether it is within
+// the limit. When it is not, it also returns how long to wait.
+func (l *Limiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget the requests that left the window, and keys without any
+	start := now.Add(-l.window)
+	for k, times := range l.seen {
+		i := 0
+		for i < len(times) && !times[i].After(start) {
+			i++
+		}
+		if i == len(times) {
+			delete(l.seen, k)
+		} else {
+			l.seen[k] = times[i:]
+		}
+	}
+
+	times := l.seen[key]
+	if len(times) >= l.limit {
+		return false, times[0].Sub(start)
+	}
+	l.seen[key] = append(times, now)
+	return true, 0
+}
@@ -0,0 +1,170 @@
+package contact
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP accepts mail on a local port and hands every message to the
+// returned channel. reject makes it refuse every recipient.
+func fakeSMTP(t *testing.T, reject bool) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, reject, messages)
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func serveSMTP(conn net.Conn, reject bool, messages chan<- string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost fake SMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch {
+		case command == "EHLO" || command == "HELO":
+			reply("250 localhost")
+		case command == "RCPT" && reject:
+			reply("550 no such user")
+		case command == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			messages <- data.String()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func testMessage() Message {
+	return Message{
+		Name:     "Zoë Recruiter",
+		Email:    "zoe@example.com",
+		Text:     "Hello,\nwe have an opening.",
+		Received: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	addr, messages := fakeSMTP(t, false)
+	mailer := &SMTPMailer{Addr: addr, From: "cv@example.com", To: "jane@example.com", Timeout: 5 * time.Second}
+
+	if err := mailer.Send(context.Background(), testMessage()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	got := <-messages
+	for _, want := range []string{
+		"From: cv@example.com\r\n",
+		"To: jane@example.com\r\n",
+		"Reply-To: =?utf-8?q?Zo=C3=AB_Recruiter?= <zoe@example.com>\r\n",
+		"Subject: =?utf-8?q?CV_contact_from_Zo=C3=AB_Recruiter?=\r\n",
+		"we have an opening.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSMTPMailerRejected(t *testing.T) {
+	addr, _ := fakeSMTP(t, true)
+	mailer := &SMTPMailer{Addr: addr, From: "cv@example.com", To: "nobody@example.com", Timeout: 5 * time.Second}
+	if err := mailer.Send(context.Background(), testMessage()); err == nil {
+		t.Error("Send() to a refused recipient succeeded")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testMessage().Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	bad := Message{Name: "Eve\r\nBcc: victim@example.com", Email: "Eve <eve@example.com>", Text: " "}
+	err := bad.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want errors")
+	}
+	for _, want := range []string{"name must be a single line", "email is not a valid address", "message is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error does not mention %q: %v", want, err)
+		}
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	outbox := NewOutbox(path)
+	for range 2 {
+		if err := outbox.Save(testMessage()); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 || !strings.Contains(string(data), `"email":"zoe@example.com"`) {
+		t.Errorf("outbox = %q, want two messages", data)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(2, time.Hour)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := range 2 {
+		if ok, _ := limiter.Allow("192.0.2.1", now.Add(time.Duration(i)*time.Minute)); !ok {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+	ok, wait := limiter.Allow("192.0.2.1", now.Add(10*time.Minute))
+	if ok || wait != 50*time.Minute {
+		t.Errorf("third request = %v, wait %v, want limited for 50m", ok, wait)
+	}
+	if ok, _ := limiter.Allow("192.0.2.2", now.Add(10*time.Minute)); !ok {
+		t.Error("another address was limited")
+	}
+	if ok, _ := limiter.Allow("192.0.2.1", now.Add(time.Hour+time.Second)); !ok {
+		t.Error("the window did not slide")
+	}
+}
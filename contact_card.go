@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"strconv"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/vcard"
+)
+
+const (
+	// QR code content kinds
+	QRContentVCard = "vcard"
+	QRContentURL   = "url"
+
+	// QR code image sizes in pixels
+	DefaultQRSize = 256
+	MaxQRSize     = 1024
+
+	// QRQuietZoneRatio is the image size divided by the width of the white border
+	QRQuietZoneRatio = 10
+)
+
+// contactCard renders the contact details as a vCard, with the photo
+// resolved against the address of the site
+func contactCard(resumeData *components.ResumeData) []byte {
+	personal := resumeData.Personal
+	personal.Photo = components.PhotoURL(*resumeData)
+	return vcard.FromPersonal(personal)
+}
+
+// qrCodeContent returns the text encoded into the QR code. An empty kind
+// falls back to the qrCode.content setting and then to the vCard.
+func qrCodeContent(resumeData *components.ResumeData, kind string) (string, error) {
+	if kind == "" {
+		kind = resumeData.QRCode.Content
+	}
+
+	switch kind {
+	case "", QRContentVCard:
+		return string(contactCard(resumeData)), nil
+	case QRContentURL:
+		url := components.SiteURL(*resumeData)
+		if url == "" {
+			return "", fmt.Errorf("neither site.baseURL nor personal.website is set")
+		}
+		return url, nil
+	default:
+		return "", fmt.Errorf("unknown QR code content %q", kind)
+	}
+}
+
+// serveVCard serves the contact details as a vCard
+func serveVCard(resumeData *components.ResumeData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		card := contactCard(resumeData)
+
+		w.Header().Set("Content-Disposition", "attachment; filename=contact.vcf")
+		w.Header().Set("Content-Type", vcard.ContentType)
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(card)))
+
+		_, err := w.Write(card)
+		if err != nil {
+			http.Error(w, "Failed to write vCard to response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// serveQRCode serves a PNG QR code of the vCard or the CV URL.
+// Query parameters: content=vcard|url, size=<pixels>.
+func serveQRCode(resumeData *components.ResumeData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		content, err := qrCodeContent(resumeData, r.URL.Query().Get("content"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		size := DefaultQRSize
+		if value := r.URL.Query().Get("size"); value != "" {
+			size, err = strconv.Atoi(value)
+			if err != nil || size <= 0 || size > MaxQRSize {
+				http.Error(w, fmt.Sprintf("size must be between 1 and %d", MaxQRSize), http.StatusBadRequest)
+				return
+			}
+		}
+
+		pngData, err := renderQRCode(content, size)
+		if err != nil {
+			http.Error(w, "Failed to render QR code: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pngData)))
+		_, err = w.Write(pngData)
+		if err != nil {
+			http.Error(w, "Failed to write QR code to response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// renderQRCode renders content as a size x size PNG QR code surrounded by
+// the white quiet zone scanners need to find the code
+func renderQRCode(content string, size int) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding QR code: %w", err)
+	}
+
+	margin := size / QRQuietZoneRatio
+	code, err = barcode.Scale(code, size-2*margin, size-2*margin)
+	if err != nil {
+		return nil, fmt.Errorf("error scaling QR code: %w", err)
+	}
+
+	canvas := image.NewGray(image.Rect(0, 0, size, size))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(canvas, code.Bounds().Add(image.Pt(margin, margin)), code, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("error encoding PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
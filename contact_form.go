@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/access"
+	"github.com/fairytale5571/cv_onopchenko/contact"
+)
+
+const (
+	// SMTPPasswordEnv is read when -smtp-password is not given
+	SMTPPasswordEnv = "CV_SMTP_PASSWORD"
+
+	// DefaultContactOutbox keeps the messages the SMTP server did not take
+	DefaultContactOutbox = "contact-outbox.jsonl"
+
+	// DefaultContactRate is the number of messages accepted per address per hour
+	DefaultContactRate = 5
+
+	// Forms submitted sooner than ContactMinFillTime after the page was
+	// loaded are from bots; older than ContactFormMaxAge must be reloaded
+	ContactMinFillTime = 3 * time.Second
+	ContactFormMaxAge  = 24 * time.Hour
+
+	// contactMaxBody bounds the size of a submitted form
+	contactMaxBody = 64 << 10
+)
+
+// Replies of the contact form
+const (
+	contactSent    = "Thank you, your message was sent."
+	contactExpired = "The form has expired, reload the page and try again."
+	contactFailed  = "Sorry, your message could not be delivered. Please try again later."
+)
+
+// contactForm accepts the messages posted to /contact
+type contactForm struct {
+	mailer  contact.Mailer
+	outbox  *contact.Outbox
+	limiter *contact.Limiter
+	// tokens sign the time the form was rendered, with a key of this process
+	tokens *access.Signer
+	now    func() time.Time
+}
+
+func newContactForm(mailer contact.Mailer, outbox *contact.Outbox, limiter *contact.Limiter) (*contactForm, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating contact form key: %w", err)
+	}
+	return &contactForm{
+		mailer:  mailer,
+		outbox:  outbox,
+		limiter: limiter,
+		tokens:  access.NewSigner(hex.EncodeToString(key)),
+		now:     time.Now,
+	}, nil
+}
+
+// token is rendered into the form; it carries the time the page was loaded
+func (f *contactForm) token() string {
+	return f.tokens.Sign(f.now().Add(ContactFormMaxAge))
+}
+
+func (f *contactForm) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	now := f.now()
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if ok, wait := f.limiter.Allow(ip, now); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		contactReply(w, r, http.StatusTooManyRequests, "Too many messages, please try again later.")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, contactMaxBody)
+	if err := r.ParseForm(); err != nil {
+		contactReply(w, r, http.StatusBadRequest, "The form could not be read.")
+		return
+	}
+
+	// Bots fill the hidden field and submit at once. They get the usual
+	// reply so that they do not learn to avoid the checks.
+	if r.PostFormValue("website") != "" {
+		slog.Info("contact form spam dropped", "reason", "honeypot", "remote", ip)
+		contactReply(w, r, http.StatusOK, contactSent)
+		return
+	}
+	expires, err := f.tokens.Verify(r.PostFormValue("token"), now)
+	if err != nil {
+		contactReply(w, r, http.StatusBadRequest, contactExpired)
+		return
+	}
+	if loaded := expires.Add(-ContactFormMaxAge); now.Sub(loaded) < ContactMinFillTime {
+		slog.Info("contact form spam dropped", "reason", "too fast", "remote", ip)
+		contactReply(w, r, http.StatusOK, contactSent)
+		return
+	}
+
+	msg := contact.Message{
+		Name:     strings.TrimSpace(r.PostFormValue("name")),
+		Email:    strings.TrimSpace(r.PostFormValue("email")),
+		Text:     strings.TrimSpace(r.PostFormValue("message")),
+		Received: now,
+	}
+	if err := msg.Validate(); err != nil {
+		contactReply(w, r, http.StatusBadRequest, strings.ReplaceAll(err.Error(), "\n", "; "))
+		return
+	}
+
+	err = f.mailer.Send(r.Context(), msg)
+	if err == nil {
+		contactReply(w, r, http.StatusOK, contactSent)
+		return
+	}
+
+	// Keep the message for later rather than lose it
+	slog.Error("error sending contact message, saving it to the outbox", "error", err)
+	if err := f.outbox.Save(msg); err != nil {
+		slog.Error("error saving contact message", "error", err)
+		contactReply(w, r, http.StatusInternalServerError, contactFailed)
+		return
+	}
+	contactReply(w, r, http.StatusAccepted, contactSent)
+}
+
+// contactReply answers the script of the page with JSON and a plain form
+// submission with text
+func contactReply(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if !strings.Contains(r.Header.Get("Accept"), "application/json") {
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(struct {
+		OK      bool   `json:"ok"`
+		Message string `json:"message"`
+	}{status < http.StatusBadRequest, message})
+	if err != nil {
+		slog.Error("error encoding contact reply", "error", err)
+	}
+}
+
+// enableContact shows the contact form and accepts its messages
+func (s *site) enableContact(form *contactForm) {
+	s.contact = form
+}
+
+// contactOptions are the serve flags of the contact form
+type contactOptions struct {
+	smtpAddr     string
+	smtpUser     string
+	smtpPassword string
+	from         string
+	to           string
+	outbox       string
+	rate         int
+}
+
+func (o *contactOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.smtpAddr, "smtp-addr", "", "SMTP server host:port for the contact form; the form is hidden without one")
+	flags.StringVar(&o.smtpUser, "smtp-user", "", "SMTP user name")
+	flags.StringVar(&o.smtpPassword, "smtp-password", os.Getenv(SMTPPasswordEnv), "SMTP password (default $"+SMTPPasswordEnv+")")
+	flags.StringVar(&o.from, "contact-from", "", "sender address of contact messages (default the recipient)")
+	flags.StringVar(&o.to, "contact-to", "", "recipient of contact messages (default personal.email)")
+	flags.StringVar(&o.outbox, "contact-outbox", DefaultContactOutbox, "file keeping the messages that could not be sent")
+	flags.IntVar(&o.rate, "contact-rate", DefaultContactRate, "messages accepted per IP address per hour")
+}
+
+// form builds the contact form, sending to email unless -contact-to is set.
+// It returns nil when no SMTP server is configured.
+func (o *contactOptions) form(email string) (*contactForm, error) {
+	if o.smtpAddr == "" {
+		return nil, nil
+	}
+
+	to := o.to
+	if to == "" {
+		to = email
+	}
+	if to == "" {
+		return nil, errors.New("the contact form needs -contact-to or personal.email")
+	}
+	from := o.from
+	if from == "" {
+		from = to
+	}
+	if o.rate < 1 {
+		return nil, errors.New("-contact-rate must be at least 1")
+	}
+
+	mailer := &contact.SMTPMailer{
+		Addr:     o.smtpAddr,
+		Username: o.smtpUser,
+		Password: o.smtpPassword,
+		From:     from,
+		To:       to,
+		Timeout:  DefaultWriteTimeout / 2,
+	}
+	return newContactForm(mailer, contact.NewOutbox(o.outbox), contact.NewLimiter(o.rate, time.Hour))
+}
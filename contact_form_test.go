@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/contact"
+)
+
+// mailerFunc lets a function stand in for the SMTP server
+type mailerFunc func(context.Context, contact.Message) error
+
+func (f mailerFunc) Send(ctx context.Context, msg contact.Message) error {
+	return f(ctx, msg)
+}
+
+func TestContactForm(t *testing.T) {
+	var sent []contact.Message
+	failing := false
+	mailer := mailerFunc(func(_ context.Context, msg contact.Message) error {
+		if failing {
+			return errors.New("connection refused")
+		}
+		sent = append(sent, msg)
+		return nil
+	})
+	outboxPath := filepath.Join(t.TempDir(), "outbox.jsonl")
+	form, err := newContactForm(mailer, contact.NewOutbox(outboxPath), contact.NewLimiter(4, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	form.now = func() time.Time { return now }
+
+	s := testSite(nil)
+	s.enableContact(form)
+	routes := s.routes()
+
+	if body := get(t, routes, "/").Body.String(); !strings.Contains(body, `id="contact-form"`) {
+		t.Fatal("the page has no contact form")
+	}
+
+	token := form.token()
+	now = now.Add(time.Minute)
+	post := func(values url.Values) *httptest.ResponseRecorder {
+		values.Set("token", token)
+		r := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, r)
+		return rec
+	}
+	message := func() url.Values {
+		return url.Values{"name": {"Zoe"}, "email": {"zoe@example.com"}, "message": {"Hello"}}
+	}
+
+	if rec := post(message()); rec.Code != http.StatusOK || len(sent) != 1 || sent[0].Email != "zoe@example.com" {
+		t.Errorf("valid message = %d %q, sent %+v", rec.Code, rec.Body.String(), sent)
+	}
+
+	spam := message()
+	spam.Set("website", "http://spam.example")
+	if rec := post(spam); rec.Code != http.StatusOK || len(sent) != 1 {
+		t.Errorf("honeypot message = %d, %d sent, want a silent drop", rec.Code, len(sent))
+	}
+
+	invalid := message()
+	invalid.Set("email", "not an address")
+	if rec := post(invalid); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "email is not a valid address") {
+		t.Errorf("invalid message = %d %q, want 400", rec.Code, rec.Body.String())
+	}
+
+	failing = true
+	if rec := post(message()); rec.Code != http.StatusAccepted {
+		t.Errorf("undeliverable message = %d, want 202", rec.Code)
+	}
+	if data, err := os.ReadFile(outboxPath); err != nil || !strings.Contains(string(data), "zoe@example.com") {
+		t.Errorf("outbox = %q, %v, want the undelivered message", data, err)
+	}
+
+	// Every post counts against the limit of four an hour
+	if rec := post(message()); rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("fifth message = %d, want 429 with Retry-After", rec.Code)
+	}
+}
+
+func TestContactFormTiming(t *testing.T) {
+	var sent int
+	mailer := mailerFunc(func(context.Context, contact.Message) error { sent++; return nil })
+	form, err := newContactForm(mailer, contact.NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl")), contact.NewLimiter(10, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	form.now = func() time.Time { return now }
+	token := form.token()
+
+	submit := func(token string) int {
+		values := url.Values{"name": {"Zoe"}, "email": {"zoe@example.com"}, "message": {"Hello"}, "token": {token}}
+		r := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		form.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	now = now.Add(time.Second)
+	if code := submit(token); code != http.StatusOK || sent != 0 {
+		t.Errorf("form sent after a second = %d, %d sent, want a silent drop", code, sent)
+	}
+	now = now.Add(ContactFormMaxAge)
+	if code := submit(token); code != http.StatusBadRequest {
+		t.Errorf("form sent after a day = %d, want 400", code)
+	}
+	if code := submit("forged.token"); code != http.StatusBadRequest {
+		t.Errorf("forged token = %d, want 400", code)
+	}
+}
@@ -0,0 +1,313 @@
+// Package docx renders the resume as a Word document (Office Open XML)
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+// ContentType is the MIME type of the rendered document
+const ContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// Paragraph styles declared in word/styles.xml
+const (
+	styleTitle    = "Title"
+	styleSubtitle = "Subtitle"
+	styleHeading1 = "Heading1"
+	styleHeading2 = "Heading2"
+	styleList     = "ListBullet"
+)
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>`
+
+const packageRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+const documentRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+// Colors match the PDF export: deep blue headings, indigo accents
+const stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Montserrat" w:hAnsi="Montserrat" w:cs="Arial"/><w:sz w:val="20"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="80"/></w:pPr></w:pPrDefault></w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:b/><w:color w:val="003366"/><w:sz w:val="52"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:color w:val="003366"/><w:sz w:val="32"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="240"/><w:pBdr><w:bottom w:val="single" w:sz="8" w:space="1" w:color="003366"/></w:pBdr><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:color w:val="003366"/><w:sz w:val="32"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="160"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:color w:val="111827"/><w:sz w:val="24"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/><w:pPr><w:ind w:left="360" w:hanging="360"/></w:pPr></w:style>
+</w:styles>`
+
+// run is a piece of a paragraph with uniform formatting
+type run struct {
+	text   string
+	bold   bool
+	italic bool
+}
+
+// document accumulates the body of word/document.xml
+type document struct {
+	body bytes.Buffer
+}
+
+func (d *document) paragraph(style string, runs ...run) {
+	d.body.WriteString("<w:p>")
+	if style != "" {
+		d.body.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	for _, r := range runs {
+		d.body.WriteString("<w:r>")
+		if r.bold || r.italic {
+			d.body.WriteString("<w:rPr>")
+			if r.bold {
+				d.body.WriteString("<w:b/>")
+			}
+			if r.italic {
+				d.body.WriteString("<w:i/>")
+			}
+			d.body.WriteString("</w:rPr>")
+		}
+		d.body.WriteString(`<w:t xml:space="preserve">`)
+		xml.EscapeText(&d.body, []byte(r.text))
+		d.body.WriteString("</w:t></w:r>")
+	}
+	d.body.WriteString("</w:p>")
+}
+
+func (d *document) text(style, text string) {
+	d.paragraph(style, run{text: text})
+}
+
+func (d *document) bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+	b.Write(d.body.Bytes())
+	// A4 with 2 cm margins
+	b.WriteString(`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="709" w:footer="709" w:gutter="0"/></w:sectPr>`)
+	b.WriteString("</w:body></w:document>")
+	return b.Bytes()
+}
+
+// Render builds the .docx package for the resume
+func Render(data components.ResumeData) ([]byte, error) {
+	var d document
+	p := data.Personal
+
+	d.text(styleTitle, p.Name)
+	if p.Title != "" {
+		d.text(styleSubtitle, p.Title)
+	}
+
+	d.text(styleHeading1, "Contact Information")
+	for _, contact := range []struct{ label, value string }{
+		{"Email", p.Email},
+		{"Phone", p.Phone},
+		{"Location", p.Location},
+		{"LinkedIn", p.Linkedin},
+		{"GitHub", p.Github},
+		{"Website", p.Website},
+	} {
+		if contact.value != "" {
+			d.paragraph("", run{text: contact.label + ": ", bold: true}, run{text: contact.value})
+		}
+	}
+
+	if p.Summary != "" {
+		d.text(styleHeading1, "Summary")
+		d.text("", strings.TrimSpace(p.Summary))
+	}
+
+	for _, section := range components.Sections(data) {
+		switch section {
+		case components.SectionSkills:
+			if len(data.Skills) > 0 {
+				d.text(styleHeading1, "Skills")
+				for _, category := range data.Skills {
+					d.paragraph("", run{text: category.Category + ": ", bold: true}, run{text: names(category.Items)})
+				}
+			}
+		case components.SectionExperience:
+			if len(data.Experience) > 0 {
+				d.text(styleHeading1, "Experience")
+				for _, exp := range data.Experience {
+					d.text(styleHeading2, exp.Position+" — "+exp.Company)
+					d.paragraph("", run{text: details(exp.StartDate+" - "+exp.EndDate, exp.Location, exp.EmploymentType), italic: true})
+					for _, desc := range exp.Description {
+						d.text(styleList, "• "+desc)
+					}
+					if len(exp.Technologies) > 0 {
+						d.paragraph("", run{text: "Technologies: ", bold: true}, run{text: names(exp.Technologies)})
+					}
+				}
+			}
+		case components.SectionEducation:
+			if len(data.Education) > 0 {
+				d.text(styleHeading1, "Education")
+				for _, edu := range data.Education {
+					d.text(styleHeading2, edu.Degree)
+					d.text("", edu.Institution)
+					d.paragraph("", run{text: details(edu.StartDate+" - "+edu.EndDate, edu.Location), italic: true})
+				}
+			}
+		case components.SectionProjects:
+			if len(data.Projects) > 0 {
+				d.text(styleHeading1, "Projects")
+				for _, project := range data.Projects {
+					d.text(styleHeading2, project.Name)
+					if project.Description != "" {
+						d.text("", strings.TrimSpace(project.Description))
+					}
+					if project.Link != "" {
+						d.text("", project.Link)
+					}
+					if len(project.Technologies) > 0 {
+						d.paragraph("", run{text: "Technologies: ", bold: true}, run{text: names(project.Technologies)})
+					}
+				}
+			}
+		case components.SectionContributions:
+			if len(data.Contributions) > 0 {
+				d.text(styleHeading1, "Open Source")
+				for _, contribution := range data.Contributions {
+					d.text(styleHeading2, contribution.Repo)
+					if summary := components.ContributionSummary(contribution); summary != "" {
+						d.text("", summary)
+					}
+					if period := components.Period(contribution.StartDate, contribution.EndDate); period != "" {
+						d.paragraph("", run{text: period, italic: true})
+					}
+					if contribution.Description != "" {
+						d.text("", strings.TrimSpace(contribution.Description))
+					}
+					if contribution.URL != "" {
+						d.text("", contribution.URL)
+					}
+				}
+			}
+		case components.SectionPublications:
+			if len(data.Publications) > 0 {
+				d.text(styleHeading1, "Publications")
+				for _, publication := range data.Publications {
+					d.text(styleHeading2, publication.Title)
+					if publication.Publisher != "" {
+						d.text("", publication.Publisher)
+					}
+					if publication.Date != "" {
+						d.paragraph("", run{text: publication.Date, italic: true})
+					}
+					if publication.Summary != "" {
+						d.text("", strings.TrimSpace(publication.Summary))
+					}
+					if publication.URL != "" {
+						d.text("", publication.URL)
+					}
+				}
+			}
+		case components.SectionTalks:
+			if len(data.Talks) > 0 {
+				d.text(styleHeading1, "Talks")
+				for _, talk := range data.Talks {
+					d.text(styleHeading2, talk.Title)
+					if venue := components.TalkVenue(talk); venue != "" {
+						d.text("", venue)
+					}
+					if talk.Date != "" {
+						d.paragraph("", run{text: talk.Date, italic: true})
+					}
+					if talk.Summary != "" {
+						d.text("", strings.TrimSpace(talk.Summary))
+					}
+					if talk.URL != "" {
+						d.text("", talk.URL)
+					}
+				}
+			}
+		case components.SectionCertifications:
+			if len(data.Certifications) > 0 {
+				d.text(styleHeading1, "Certifications")
+				for _, cert := range data.Certifications {
+					d.text(styleHeading2, cert.Name)
+					if cert.Issuer != "" {
+						d.text("", cert.Issuer)
+					}
+					if dates := components.CertificationDates(cert); dates != "" {
+						d.paragraph("", run{text: dates, italic: true})
+					}
+					if cert.CredentialID != "" {
+						d.paragraph("", run{text: "Credential ID: ", bold: true}, run{text: cert.CredentialID})
+					}
+					if cert.URL != "" {
+						d.text("", cert.URL)
+					}
+				}
+			}
+		case components.SectionLanguages:
+			if len(data.Languages) > 0 {
+				d.text(styleHeading1, "Languages")
+				for _, lang := range data.Languages {
+					d.text(styleList, "• "+lang.Language+" ("+lang.Proficiency+")")
+				}
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, part := range []struct {
+		name string
+		body []byte
+	}{
+		{"[Content_Types].xml", []byte(contentTypesXML)},
+		{"_rels/.rels", []byte(packageRelsXML)},
+		{"word/_rels/document.xml.rels", []byte(documentRelsXML)},
+		{"word/styles.xml", []byte(stylesXML)},
+		{"word/document.xml", d.bytes()},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func names(items []components.Link) string {
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		list = append(list, item.Name)
+	}
+	return strings.Join(list, ", ")
+}
+
+// details joins the non-empty parts of a position or degree subtitle
+func details(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.Trim(part, " -"); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, " · ")
+}
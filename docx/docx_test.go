@@ -0,0 +1,106 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func TestRender(t *testing.T) {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane <Doe>", Title: "Developer", Email: "jane@example.com"}
+	data.Experience = []components.ExperienceItem{{
+		Company:     "R&D",
+		Position:    "Developer",
+		StartDate:   "Mar 2022",
+		EndDate:     "Present",
+		Description: []string{"Built things"},
+	}}
+
+	out, err := Render(data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("output is not a zip archive: %v", err)
+	}
+
+	parts := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = body
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/styles.xml", "word/document.xml"} {
+		body, ok := parts[name]
+		if !ok {
+			t.Errorf("package is missing %s", name)
+			continue
+		}
+		if err := wellFormed(body); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", name, err)
+		}
+	}
+
+	// Text is extracted through the XML decoder, so escaping round trips
+	text := documentText(t, parts["word/document.xml"])
+	for _, want := range []string{"Jane <Doe>", "Developer — R&D", "Mar 2022 - Present", "• Built things", "Email: jane@example.com"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("document text is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func wellFormed(body []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// documentText returns the text of every paragraph, one per line
+func documentText(t *testing.T, body []byte) string {
+	t.Helper()
+
+	var b strings.Builder
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return b.String()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tok := token.(type) {
+		case xml.CharData:
+			b.Write(tok)
+		case xml.EndElement:
+			if tok.Name.Local == "p" {
+				b.WriteString("\n")
+			}
+		}
+	}
+}
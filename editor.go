@@ -0,0 +1,472 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/configedit"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+)
+
+// DefaultRevisionsDir keeps the versions of the config replaced by the editor
+const DefaultRevisionsDir = "revisions"
+
+// editor changes the lists of the config file from the /admin pages
+type editor struct {
+	// opts name the config file and how the site renders it
+	opts    options
+	history *configedit.History
+	// reload makes the site serve the saved config
+	reload func() error
+	now    func() time.Time
+
+	// mu serializes the changes, each of which rewrites the whole file
+	mu sync.Mutex
+}
+
+func newEditor(opts options, history *configedit.History, reload func() error) *editor {
+	return &editor{opts: opts, history: history, reload: reload, now: time.Now}
+}
+
+// enableEditor serves the editor to the holder of the admin token
+func (s *site) enableEditor(e *editor) {
+	s.editor = e
+}
+
+// configError is a change refused because it makes the config invalid
+type configError struct {
+	problems []string
+}
+
+func (e *configError) Error() string {
+	return "invalid config: " + strings.Join(e.problems, "; ")
+}
+
+func (e *editor) path() string {
+	return resume.LocalePath(e.opts.configPath, e.opts.locale)
+}
+
+// read parses the config file as it is on disk
+func (e *editor) read() (*configedit.Document, error) {
+	data, err := os.ReadFile(e.path())
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	return configedit.Parse(data)
+}
+
+// problems lists what resume.Validate finds wrong with doc
+func problems(doc *configedit.Document) ([]string, error) {
+	data, err := doc.Resume()
+	if err != nil {
+		return nil, err
+	}
+	if err := resume.Validate(data); err != nil {
+		return strings.Split(err.Error(), "\n"), nil
+	}
+	return nil, nil
+}
+
+// problemIndex matches the list indices in a problem, such as the 1 of
+// experience[1].company
+var problemIndex = regexp.MustCompile(`\[\d+\]`)
+
+// newProblems returns the problems of after that before did not have. They
+// are compared without list indices: moving or deleting an entry shifts the
+// entries after it, and their old problems must not look new.
+func newProblems(before, after []string) []string {
+	known := map[string]int{}
+	for _, problem := range before {
+		known[problemIndex.ReplaceAllString(problem, "[]")]++
+	}
+	var added []string
+	for _, problem := range after {
+		key := problemIndex.ReplaceAllString(problem, "[]")
+		if known[key] > 0 {
+			known[key]--
+			continue
+		}
+		added = append(added, problem)
+	}
+	return added
+}
+
+// change applies edit to the config and saves it. The change is refused
+// with a configError when it brings new problems; the ones the file already
+// had are left for the owner to fix.
+func (e *editor) change(edit func(doc *configedit.Document) error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	doc, err := e.read()
+	if err != nil {
+		return err
+	}
+	before, err := problems(doc)
+	if err != nil {
+		return err
+	}
+	if err := edit(doc); err != nil {
+		return err
+	}
+	after, err := problems(doc)
+	if err != nil {
+		return err
+	}
+
+	if added := newProblems(before, after); len(added) > 0 {
+		return &configError{problems: added}
+	}
+
+	data, err := doc.Encode()
+	if err != nil {
+		return err
+	}
+	return e.save(data)
+}
+
+// save replaces the config file with data, keeping the old version in the
+// history, and reloads the site. Callers hold mu.
+func (e *editor) save(data []byte) error {
+	old, err := os.ReadFile(e.path())
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	revision, err := e.history.Save(old, e.now())
+	if err != nil {
+		return err
+	}
+	if err := configedit.WriteFile(e.path(), data); err != nil {
+		return err
+	}
+	slog.Info("config changed from the editor", "path", e.path(), "revision", revision.Name)
+
+	if err := e.reload(); err != nil {
+		return fmt.Errorf("config saved, but the site was not reloaded: %w", err)
+	}
+	return nil
+}
+
+// routes registers the editor pages behind the login of token
+func (e *editor) routes(mux *http.ServeMux, token string) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle(pattern, requireLogin(token, handler))
+	}
+	handle("GET /admin", e.serveDashboard)
+	handle("GET /admin/edit/{section}/{index}", e.serveForm)
+	handle("POST /admin/edit/{section}/{index}", e.serveEdit)
+	handle("POST /admin/move/{section}/{index}", e.serveMove)
+	handle("POST /admin/delete/{section}/{index}", e.serveDelete)
+	handle("GET /admin/revisions", e.serveRevisions)
+	handle("GET /admin/revisions/{name}", e.serveRevision)
+	handle("POST /admin/revisions/{name}/restore", e.serveRestore)
+}
+
+// render writes page with status
+func render(w http.ResponseWriter, r *http.Request, status int, page templ.Component) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := page.Render(r.Context(), w); err != nil {
+		slog.Error("error rendering page", "path", r.URL.Path, "error", err)
+	}
+}
+
+// fail answers a failed change: unknown entries are 404, the rest 500
+func fail(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNoEntry) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	slog.Error("editor error", "error", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+var errNoEntry = errors.New("no such entry")
+
+// entry reads the section and index of the URL; new entries have index -1
+func entry(r *http.Request) (configedit.Section, int, error) {
+	section, ok := configedit.FindSection(r.PathValue("section"))
+	if !ok {
+		return section, 0, fmt.Errorf("%w: unknown section %q", errNoEntry, r.PathValue("section"))
+	}
+	if r.PathValue("index") == "new" {
+		return section, -1, nil
+	}
+	i, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || i < 0 {
+		return section, 0, fmt.Errorf("%w: %s has no entry %q", errNoEntry, section.Key, r.PathValue("index"))
+	}
+	return section, i, nil
+}
+
+// checkEntry reports the entries that the document does not have
+func checkEntry(doc *configedit.Document, section configedit.Section, i int) error {
+	if i >= doc.Len(section.Key) {
+		return fmt.Errorf("%w: %s has no entry %d", errNoEntry, section.Key, i)
+	}
+	return nil
+}
+
+func (e *editor) serveDashboard(w http.ResponseWriter, r *http.Request) {
+	doc, err := e.read()
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	var sections []components.AdminSection
+	for _, section := range configedit.Sections {
+		view := components.AdminSection{Key: section.Key, Title: section.Title}
+		for i := range doc.Len(section.Key) {
+			name := "(untitled)"
+			if values, err := doc.Values(section, i); err == nil && values[section.Fields[0].Key] != "" {
+				name = values[section.Fields[0].Key]
+			}
+			view.Items = append(view.Items, name)
+		}
+		sections = append(sections, view)
+	}
+	found, err := problems(doc)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	render(w, r, http.StatusOK, components.AdminDashboard(sections, found))
+}
+
+// form builds the view of the form of an entry
+func form(section configedit.Section, i int, values configedit.Values, errs []string) components.AdminForm {
+	view := components.AdminForm{
+		Section: section.Key,
+		Title:   "Edit " + section.Title,
+		Index:   strconv.Itoa(i),
+		Errors:  errs,
+	}
+	if i < 0 {
+		view.Title, view.Index = "Add to "+section.Title, "new"
+	}
+	for _, field := range section.Fields {
+		view.Fields = append(view.Fields, components.AdminField{
+			Key:   field.Key,
+			Label: field.Label,
+			Kind:  field.Kind,
+			Value: values[field.Key],
+		})
+	}
+	return view
+}
+
+func (e *editor) serveForm(w http.ResponseWriter, r *http.Request) {
+	section, i, err := entry(r)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	values := configedit.Values{}
+	if i >= 0 {
+		doc, err := e.read()
+		if err == nil {
+			err = checkEntry(doc, section, i)
+		}
+		if err == nil {
+			values, err = doc.Values(section, i)
+		}
+		if err != nil {
+			fail(w, err)
+			return
+		}
+	}
+	render(w, r, http.StatusOK, components.AdminEdit(form(section, i, values, nil)))
+}
+
+// serveEdit saves the form of an entry, or previews the resume with it
+func (e *editor) serveEdit(w http.ResponseWriter, r *http.Request) {
+	section, i, err := entry(r)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	values := configedit.Values{}
+	for _, field := range section.Fields {
+		values[field.Key] = r.PostFormValue(field.Key)
+	}
+	set := func(doc *configedit.Document) error {
+		if i < 0 {
+			return doc.Set(section, doc.Len(section.Key), values)
+		}
+		if err := checkEntry(doc, section, i); err != nil {
+			return err
+		}
+		return doc.Set(section, i, values)
+	}
+
+	if r.PostFormValue("action") == "preview" {
+		e.servePreview(w, r, set)
+		return
+	}
+
+	err = e.change(set)
+	var invalid *configError
+	if errors.As(err, &invalid) {
+		render(w, r, http.StatusUnprocessableEntity, components.AdminEdit(form(section, i, values, invalid.problems)))
+		return
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+// servePreview renders the resume as the site would with edit applied,
+// without saving it. The private fields are shown, as to the owner.
+func (e *editor) servePreview(w http.ResponseWriter, r *http.Request, edit func(doc *configedit.Document) error) {
+	doc, err := e.read()
+	if err == nil {
+		err = edit(doc)
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	data, err := doc.Resume()
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	data.Page.Locale = e.opts.locale
+	data, err = resume.ApplyVariant(data, e.opts.variant)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	data.Page.Theme = e.opts.theme
+	render(w, r, http.StatusOK, components.Resume(data))
+}
+
+func (e *editor) serveMove(w http.ResponseWriter, r *http.Request) {
+	section, i, err := entry(r)
+	if err == nil && i < 0 {
+		err = fmt.Errorf("%w: new entries cannot be moved", errNoEntry)
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	to, err := strconv.Atoi(r.PostFormValue("to"))
+	if err != nil {
+		http.Error(w, "to must be the new position", http.StatusBadRequest)
+		return
+	}
+
+	err = e.change(func(doc *configedit.Document) error {
+		if err := checkEntry(doc, section, i); err != nil {
+			return err
+		}
+		if err := checkEntry(doc, section, to); err != nil {
+			return err
+		}
+		return doc.Move(section.Key, i, to)
+	})
+	e.finish(w, r, err)
+}
+
+func (e *editor) serveDelete(w http.ResponseWriter, r *http.Request) {
+	section, i, err := entry(r)
+	if err == nil && i < 0 {
+		err = fmt.Errorf("%w: new entries cannot be deleted", errNoEntry)
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	err = e.change(func(doc *configedit.Document) error {
+		if err := checkEntry(doc, section, i); err != nil {
+			return err
+		}
+		return doc.Delete(section.Key, i)
+	})
+	e.finish(w, r, err)
+}
+
+// finish answers a change made from the dashboard
+func (e *editor) finish(w http.ResponseWriter, r *http.Request, err error) {
+	var invalid *configError
+	if errors.As(err, &invalid) {
+		http.Error(w, invalid.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+func (e *editor) serveRevisions(w http.ResponseWriter, r *http.Request) {
+	revisions, err := e.history.List()
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	var views []components.AdminRevision
+	for _, revision := range revisions {
+		views = append(views, components.AdminRevision{
+			Name: revision.Name,
+			Time: revision.Time.Local().Format("2006-01-02 15:04:05"),
+			Size: revision.Size,
+		})
+	}
+	render(w, r, http.StatusOK, components.AdminRevisions(views))
+}
+
+// serveRevision shows a revision as plain YAML
+func (e *editor) serveRevision(w http.ResponseWriter, r *http.Request) {
+	data, err := e.history.Read(r.PathValue("name"))
+	if errors.Is(err, configedit.ErrNoRevision) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+// serveRestore puts a revision back. The config it replaces becomes a
+// revision itself, so a restore can be undone too.
+func (e *editor) serveRestore(w http.ResponseWriter, r *http.Request) {
+	data, err := e.history.Read(r.PathValue("name"))
+	if errors.Is(err, configedit.ErrNoRevision) {
+		http.NotFound(w, r)
+		return
+	}
+	if err == nil {
+		_, err = configedit.Parse(data)
+	}
+	if err == nil {
+		e.mu.Lock()
+		err = e.save(data)
+		e.mu.Unlock()
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
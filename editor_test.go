@@ -0,0 +1,245 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/configedit"
+)
+
+const editorConfig = `personal:
+  name: "Jane Doe" # shown in the header
+  title: "Developer"
+
+experience:
+  # Current job
+  - company: "ACME"
+    position: "Go Developer"
+    startDate: "Mar 2022"
+    endDate: "Present"
+
+  - company: "Studio"
+    position: "Game Designer"
+    startDate: "2018"
+    endDate: "Feb 2022"
+`
+
+// editorSite serves an editor of a temporary config; reloads counts the
+// saved changes
+func editorSite(t *testing.T) (routes http.Handler, path string, history *configedit.History, reloads *int) {
+	t.Helper()
+	dir := t.TempDir()
+	path = filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(editorConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reloads = new(int)
+	history = configedit.NewHistory(filepath.Join(dir, "revisions"))
+	opts := options{configPath: path, theme: components.ThemeSystem}
+	s := testSite(nil)
+	s.adminToken = "secret"
+	s.enableEditor(newEditor(opts, history, func() error {
+		*reloads++
+		return nil
+	}))
+	return s.routes(), path, history, reloads
+}
+
+// post submits a form to the editor with the admin token
+func post(t *testing.T, handler http.Handler, path string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec
+}
+
+func readConfig(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestAdminLogin(t *testing.T) {
+	routes, _, _, _ := editorSite(t)
+
+	rec := get(t, routes, "/admin")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/admin/login?next=%2Fadmin" {
+		t.Fatalf("/admin without a session = %d %q, want a redirect to the login", rec.Code, rec.Header().Get("Location"))
+	}
+
+	login := func(token, next string) *httptest.ResponseRecorder {
+		form := url.Values{"token": {token}, "next": {next}}
+		r := httptest.NewRequest(http.MethodPost, "/admin/login", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, r)
+		return rec
+	}
+	if rec := login("wrong", "/admin"); rec.Code != http.StatusUnauthorized || len(rec.Result().Cookies()) != 0 {
+		t.Errorf("login with a wrong token = %d, want 401 without a session", rec.Code)
+	}
+	if rec := login("secret", "https://example.com/"); rec.Header().Get("Location") != "/admin" {
+		t.Errorf("login redirected to %q, want /admin", rec.Header().Get("Location"))
+	}
+
+	rec = login("secret", "/admin/revisions")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/admin/revisions" {
+		t.Fatalf("login = %d %q, want a redirect back", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != AdminCookie || !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("login cookies = %v, want a strict HttpOnly session", cookies)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	r.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Game Designer") {
+		t.Errorf("/admin with a session = %d, want the dashboard", rec.Code)
+	}
+
+	// Changes from other sites are refused even with the session
+	r = httptest.NewRequest(http.MethodPost, "/admin/delete/experience/0", nil)
+	r.AddCookie(cookies[0])
+	r.Header.Set("Origin", "https://attacker.test")
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("cross-site delete = %d, want 403", rec.Code)
+	}
+}
+
+func TestEditorSave(t *testing.T) {
+	routes, path, history, reloads := editorSite(t)
+
+	form := url.Values{
+		"action":    {"save"},
+		"company":   {"ACME"},
+		"position":  {"Senior Go Developer"},
+		"startDate": {"Mar 2022"},
+		"endDate":   {"Present"},
+	}
+	rec := post(t, routes, "/admin/edit/experience/0", form)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("save = %d %s, want 303", rec.Code, rec.Body.String())
+	}
+
+	want := strings.Replace(editorConfig, `"Go Developer"`, `"Senior Go Developer"`, 1)
+	if got := readConfig(t, path); got != want {
+		t.Errorf("config after save:\n%s\nwant:\n%s", got, want)
+	}
+	revisions, err := history.List()
+	if err != nil || len(revisions) != 1 {
+		t.Fatalf("revisions = %v, %v, want one", revisions, err)
+	}
+	if *reloads != 1 {
+		t.Errorf("site reloaded %d times, want 1", *reloads)
+	}
+
+	// The old version comes back with a restore
+	if rec := post(t, routes, "/admin/revisions/"+revisions[0].Name+"/restore", nil); rec.Code != http.StatusSeeOther {
+		t.Fatalf("restore = %d %s, want 303", rec.Code, rec.Body.String())
+	}
+	if got := readConfig(t, path); got != editorConfig {
+		t.Errorf("config after restore:\n%s", got)
+	}
+}
+
+func TestEditorRefusesInvalidChanges(t *testing.T) {
+	routes, path, history, reloads := editorSite(t)
+
+	form := url.Values{"action": {"save"}, "position": {"Intern"}, "startDate": {"someday"}}
+	rec := post(t, routes, "/admin/edit/experience/new", form)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid save = %d, want 422", rec.Code)
+	}
+	for _, want := range []string{"experience[2].company is required", `value="Intern"`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("form is missing %q:\n%s", want, rec.Body.String())
+		}
+	}
+
+	if got := readConfig(t, path); got != editorConfig {
+		t.Errorf("config changed by a refused save:\n%s", got)
+	}
+	if revisions, _ := history.List(); len(revisions) != 0 || *reloads != 0 {
+		t.Errorf("refused save left %d revisions and %d reloads", len(revisions), *reloads)
+	}
+}
+
+func TestEditorKeepsExistingProblems(t *testing.T) {
+	routes, path, _, _ := editorSite(t)
+	config := strings.Replace(editorConfig, `    position: "Game Designer"
+`, "", 1)
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The entry without a position moves from experience[1] to
+	// experience[0], which is not a new problem
+	if rec := post(t, routes, "/admin/move/experience/1", url.Values{"to": {"0"}}); rec.Code != http.StatusSeeOther {
+		t.Fatalf("move = %d %s, want 303", rec.Code, rec.Body.String())
+	}
+	if config := readConfig(t, path); strings.Index(config, "Studio") > strings.Index(config, "ACME") {
+		t.Errorf("config after move:\n%s", config)
+	}
+
+	// The same problem in another entry still is
+	form := url.Values{"action": {"save"}, "company": {"Initech"}, "startDate": {"2024"}}
+	if rec := post(t, routes, "/admin/edit/experience/new", form); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("save of a second entry without a position = %d, want 422", rec.Code)
+	}
+}
+
+func TestEditorPreview(t *testing.T) {
+	routes, path, _, _ := editorSite(t)
+
+	form := url.Values{"action": {"preview"}, "company": {"Initech"}, "position": {"Architect"}, "startDate": {"2024"}}
+	rec := post(t, routes, "/admin/edit/experience/new", form)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Initech") {
+		t.Errorf("preview = %d, want the resume with the new entry", rec.Code)
+	}
+	if got := readConfig(t, path); got != editorConfig {
+		t.Errorf("config changed by a preview:\n%s", got)
+	}
+}
+
+func TestEditorMoveAndDelete(t *testing.T) {
+	routes, path, _, _ := editorSite(t)
+
+	if rec := post(t, routes, "/admin/move/experience/1", url.Values{"to": {"0"}}); rec.Code != http.StatusSeeOther {
+		t.Fatalf("move = %d %s, want 303", rec.Code, rec.Body.String())
+	}
+	config := readConfig(t, path)
+	if strings.Index(config, "Studio") > strings.Index(config, "ACME") {
+		t.Errorf("config after move:\n%s", config)
+	}
+
+	if rec := post(t, routes, "/admin/delete/experience/1", nil); rec.Code != http.StatusSeeOther {
+		t.Fatalf("delete = %d %s, want 303", rec.Code, rec.Body.String())
+	}
+	if config := readConfig(t, path); strings.Contains(config, "ACME") || !strings.Contains(config, "Studio") {
+		t.Errorf("config after delete:\n%s", config)
+	}
+
+	if rec := post(t, routes, "/admin/delete/experience/5", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("delete of a missing entry = %d, want 404", rec.Code)
+	}
+	if rec := post(t, routes, "/admin/delete/personal/0", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("delete in an unknown section = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,351 @@
+// Package europass exports resume data as a Europass CV in the Europass XML
+// (SkillsPassport V3.4) format accepted by the Europass portal.
+package europass
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+const (
+	// Namespace is the XML namespace of Europass documents
+	Namespace = "http://europass.cedefop.europa.eu/Europass"
+
+	// XSDVersion is the Europass schema version the export conforms to
+	XSDVersion = "V3.4"
+
+	// configDateLayout is the date format used in config.yaml
+	configDateLayout = "Jan 2006"
+)
+
+// cefrLevels maps proficiency labels used in config.yaml to CEFR levels
+var cefrLevels = map[string]string{
+	"elementary":           "A2",
+	"limited working":      "B1",
+	"professional working": "B2",
+	"full professional":    "C1",
+	"bilingual":            "C2",
+}
+
+// nativeLevels are proficiencies that make a language a mother tongue
+var nativeLevels = map[string]bool{
+	"native":              true,
+	"native or bilingual": true,
+	"mother tongue":       true,
+}
+
+// SkillsPassport is the root element of a Europass XML document
+type SkillsPassport struct {
+	XMLName      xml.Name     `xml:"http://europass.cedefop.europa.eu/Europass SkillsPassport"`
+	Locale       string       `xml:"locale,attr"`
+	DocumentInfo DocumentInfo `xml:"DocumentInfo"`
+	LearnerInfo  LearnerInfo  `xml:"LearnerInfo"`
+}
+
+// DocumentInfo describes the document itself
+type DocumentInfo struct {
+	DocumentType   string `xml:"DocumentType"`
+	CreationDate   string `xml:"CreationDate"`
+	LastUpdateDate string `xml:"LastUpdateDate"`
+	XSDVersion     string `xml:"XSDVersion"`
+	Generator      string `xml:"Generator"`
+}
+
+// LearnerInfo holds the CV content
+type LearnerInfo struct {
+	Identification     Identification   `xml:"Identification"`
+	Headline           *Headline        `xml:"Headline,omitempty"`
+	WorkExperienceList []WorkExperience `xml:"WorkExperienceList>WorkExperience,omitempty"`
+	EducationList      []Education      `xml:"EducationList>Education,omitempty"`
+	Skills             *Skills          `xml:"Skills,omitempty"`
+}
+
+// Identification holds the name and contact information
+type Identification struct {
+	PersonName  PersonName  `xml:"PersonName"`
+	ContactInfo ContactInfo `xml:"ContactInfo"`
+}
+
+// PersonName is the name of the candidate
+type PersonName struct {
+	FirstName string `xml:"FirstName"`
+	Surname   string `xml:"Surname"`
+}
+
+// ContactInfo holds address, email, phones and websites
+type ContactInfo struct {
+	Address       *Address    `xml:"Address,omitempty"`
+	Email         *Contact    `xml:"Email,omitempty"`
+	TelephoneList []UsedValue `xml:"TelephoneList>Telephone,omitempty"`
+	WebsiteList   []UsedValue `xml:"WebsiteList>Website,omitempty"`
+}
+
+// Address is a postal address
+type Address struct {
+	Contact AddressContact `xml:"Contact"`
+}
+
+// AddressContact is the content of an address
+type AddressContact struct {
+	Municipality string `xml:"Municipality,omitempty"`
+	Country      *Label `xml:"Country,omitempty"`
+}
+
+// Contact is a single contact value
+type Contact struct {
+	Contact string `xml:"Contact"`
+}
+
+// UsedValue is a contact value together with its kind of use
+type UsedValue struct {
+	Contact string `xml:"Contact"`
+	Use     Code   `xml:"Use"`
+}
+
+// Code is a coded value from a Europass vocabulary
+type Code struct {
+	Code  string `xml:"Code"`
+	Label string `xml:"Label,omitempty"`
+}
+
+// Label is a free-text value
+type Label struct {
+	Label string `xml:"Label"`
+}
+
+// Headline is the desired or current position
+type Headline struct {
+	Type        Code  `xml:"Type"`
+	Description Label `xml:"Description"`
+}
+
+// WorkExperience is a single position in the work history
+type WorkExperience struct {
+	Period     Period       `xml:"Period"`
+	Position   Label        `xml:"Position"`
+	Activities string       `xml:"Activities,omitempty"`
+	Employer   Organisation `xml:"Employer"`
+}
+
+// Education is a single degree or course of study
+type Education struct {
+	Period       Period       `xml:"Period"`
+	Title        string       `xml:"Title"`
+	Organisation Organisation `xml:"Organisation"`
+}
+
+// Organisation is an employer or an education provider
+type Organisation struct {
+	Name        string       `xml:"Name"`
+	ContactInfo *ContactInfo `xml:"ContactInfo,omitempty"`
+}
+
+// Period is a date range; Current is set instead of To for ongoing entries
+type Period struct {
+	From    *Date `xml:"From,omitempty"`
+	To      *Date `xml:"To,omitempty"`
+	Current bool  `xml:"Current,omitempty"`
+}
+
+// Date is a partial date with gYear and gMonth attributes
+type Date struct {
+	Year  string `xml:"year,attr"`
+	Month string `xml:"month,attr,omitempty"`
+}
+
+// Skills holds the linguistic skills
+type Skills struct {
+	Linguistic Linguistic `xml:"Linguistic"`
+}
+
+// Linguistic splits languages into mother tongues and foreign languages
+type Linguistic struct {
+	MotherTongueList    []MotherTongue    `xml:"MotherTongueList>MotherTongue,omitempty"`
+	ForeignLanguageList []ForeignLanguage `xml:"ForeignLanguageList>ForeignLanguage,omitempty"`
+}
+
+// MotherTongue is a native language
+type MotherTongue struct {
+	Description Label `xml:"Description"`
+}
+
+// ForeignLanguage is a language with CEFR self-assessment levels
+type ForeignLanguage struct {
+	Description      Label             `xml:"Description"`
+	ProficiencyLevel *ProficiencyLevel `xml:"ProficiencyLevel,omitempty"`
+}
+
+// ProficiencyLevel is the CEFR self-assessment grid
+type ProficiencyLevel struct {
+	Listening         string `xml:"Listening"`
+	Reading           string `xml:"Reading"`
+	SpokenInteraction string `xml:"SpokenInteraction"`
+	SpokenProduction  string `xml:"SpokenProduction"`
+	Writing           string `xml:"Writing"`
+}
+
+// FromResumeData converts resume data into a Europass document
+func FromResumeData(data components.ResumeData, now time.Time) SkillsPassport {
+	p := data.Personal
+	firstName, surname := splitName(p.Name)
+	created := now.UTC().Format(time.RFC3339)
+
+	doc := SkillsPassport{
+		Locale: "en",
+		DocumentInfo: DocumentInfo{
+			DocumentType:   "ECV",
+			CreationDate:   created,
+			LastUpdateDate: created,
+			XSDVersion:     XSDVersion,
+			Generator:      "cv_onopchenko",
+		},
+		LearnerInfo: LearnerInfo{
+			Identification: Identification{
+				PersonName:  PersonName{FirstName: firstName, Surname: surname},
+				ContactInfo: ContactInfo{Address: toAddress(p.Location)},
+			},
+		},
+	}
+
+	contact := &doc.LearnerInfo.Identification.ContactInfo
+	if p.Email != "" {
+		contact.Email = &Contact{Contact: p.Email}
+	}
+	if p.Phone != "" {
+		contact.TelephoneList = append(contact.TelephoneList, UsedValue{Contact: p.Phone, Use: Code{Code: "mobile"}})
+	}
+	for _, website := range []string{p.Website, p.Linkedin, p.Github} {
+		if website != "" {
+			contact.WebsiteList = append(contact.WebsiteList, UsedValue{Contact: website, Use: Code{Code: "personal"}})
+		}
+	}
+
+	if p.Title != "" {
+		doc.LearnerInfo.Headline = &Headline{
+			Type:        Code{Code: "position", Label: "Position"},
+			Description: Label{Label: p.Title},
+		}
+	}
+
+	for _, exp := range data.Experience {
+		employer := Organisation{Name: exp.Company}
+		if address := toAddress(exp.Location); address != nil {
+			employer.ContactInfo = &ContactInfo{Address: address}
+		}
+		doc.LearnerInfo.WorkExperienceList = append(doc.LearnerInfo.WorkExperienceList, WorkExperience{
+			Period:     toPeriod(exp.StartDate, exp.EndDate),
+			Position:   Label{Label: exp.Position},
+			Activities: strings.Join(exp.Description, "\n"),
+			Employer:   employer,
+		})
+	}
+
+	for _, edu := range data.Education {
+		organisation := Organisation{Name: edu.Institution}
+		if address := toAddress(edu.Location); address != nil {
+			organisation.ContactInfo = &ContactInfo{Address: address}
+		}
+		doc.LearnerInfo.EducationList = append(doc.LearnerInfo.EducationList, Education{
+			Period:       toPeriod(edu.StartDate, edu.EndDate),
+			Title:        edu.Degree,
+			Organisation: organisation,
+		})
+	}
+
+	if len(data.Languages) > 0 {
+		var linguistic Linguistic
+		for _, lang := range data.Languages {
+			if nativeLevels[strings.ToLower(strings.TrimSpace(lang.Proficiency))] {
+				linguistic.MotherTongueList = append(linguistic.MotherTongueList, MotherTongue{Description: Label{Label: lang.Language}})
+				continue
+			}
+
+			foreign := ForeignLanguage{Description: Label{Label: lang.Language}}
+			if level := CEFRLevel(lang.Proficiency); level != "" {
+				foreign.ProficiencyLevel = &ProficiencyLevel{
+					Listening:         level,
+					Reading:           level,
+					SpokenInteraction: level,
+					SpokenProduction:  level,
+					Writing:           level,
+				}
+			}
+			linguistic.ForeignLanguageList = append(linguistic.ForeignLanguageList, foreign)
+		}
+		doc.LearnerInfo.Skills = &Skills{Linguistic: linguistic}
+	}
+
+	return doc
+}
+
+// Marshal renders the document as indented Europass XML with an XML header
+func Marshal(doc SkillsPassport) ([]byte, error) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding Europass XML: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// CEFRLevel maps a proficiency label to a CEFR level (A1-C2). Labels that
+// already are CEFR levels are returned as is; unknown labels map to "".
+func CEFRLevel(proficiency string) string {
+	normalized := strings.ToLower(strings.TrimSpace(proficiency))
+
+	if len(normalized) == 2 && strings.Contains("abc", normalized[:1]) && strings.Contains("12", normalized[1:]) {
+		return strings.ToUpper(normalized)
+	}
+	if nativeLevels[normalized] {
+		return "C2"
+	}
+	return cefrLevels[normalized]
+}
+
+// splitName splits a full name into first name(s) and surname
+func splitName(name string) (string, string) {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return name, ""
+	}
+	return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+}
+
+// toAddress converts "City, Country" into a Europass address
+func toAddress(location string) *Address {
+	if location == "" {
+		return nil
+	}
+
+	municipality, country, found := strings.Cut(location, ", ")
+	if !found {
+		// A single value is more likely a country ("Ukraine") than a city
+		return &Address{Contact: AddressContact{Country: &Label{Label: location}}}
+	}
+	return &Address{Contact: AddressContact{Municipality: municipality, Country: &Label{Label: country}}}
+}
+
+// toPeriod converts config.yaml dates into a Europass period
+func toPeriod(start, end string) Period {
+	period := Period{From: toDate(start)}
+	if end == "" || strings.EqualFold(end, "Present") {
+		period.Current = true
+	} else {
+		period.To = toDate(end)
+	}
+	return period
+}
+
+// toDate parses "Aug 2023" or "2023" into a Europass date
+func toDate(value string) *Date {
+	if t, err := time.Parse(configDateLayout, value); err == nil {
+		return &Date{Year: t.Format("2006"), Month: t.Format("--01")}
+	}
+	if t, err := time.Parse("2006", value); err == nil {
+		return &Date{Year: t.Format("2006")}
+	}
+	return nil
+}
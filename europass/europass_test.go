@@ -0,0 +1,182 @@
+package europass
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func fixture() components.ResumeData {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{
+		Name:     "Jane Van Doe",
+		Title:    "Golang Developer",
+		Email:    "jane@example.com",
+		Phone:    "+380000000000",
+		Location: "Kyiv, Ukraine",
+		Linkedin: "https://www.linkedin.com/in/jane",
+		Github:   "https://github.com/jane",
+	}
+	data.Experience = []components.ExperienceItem{
+		{Company: "ACME", Position: "Developer", Location: "Kyiv, Ukraine", StartDate: "Aug 2023", EndDate: "Present", Description: []string{"Built services.", "Wrote tests."}},
+		{Company: "Initech", Position: "Intern", Location: "Ukraine", StartDate: "Sep 2021", EndDate: "Jul 2022"},
+	}
+	data.Education = []components.EducationItem{
+		{Institution: "KPI", Degree: "Bachelor", Location: "Kyiv, Ukraine", StartDate: "2014", EndDate: "2018"},
+	}
+	data.Languages = []components.LanguageItem{
+		{Language: "English", Proficiency: "Limited Working"},
+		{Language: "Ukrainian", Proficiency: "Native"},
+		{Language: "German", Proficiency: "a2"},
+		{Language: "Klingon", Proficiency: "Some"},
+	}
+	return data
+}
+
+// elementPaths returns the slash-separated path of every element in the document
+func elementPaths(t *testing.T, doc []byte) map[string]bool {
+	t.Helper()
+
+	paths := make(map[string]bool)
+	var stack []string
+	decoder := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, el.Name.Local)
+			paths[strings.Join(stack, "/")] = true
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) != 0 {
+		t.Fatalf("document is not well-formed, unclosed elements: %v", stack)
+	}
+	return paths
+}
+
+func TestMarshalStructure(t *testing.T) {
+	doc, err := Marshal(FromResumeData(fixture(), time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if !bytes.HasPrefix(doc, []byte(xml.Header)) {
+		t.Error("document does not start with an XML header")
+	}
+
+	paths := elementPaths(t, doc)
+	for _, want := range []string{
+		"SkillsPassport/DocumentInfo/DocumentType",
+		"SkillsPassport/DocumentInfo/XSDVersion",
+		"SkillsPassport/LearnerInfo/Identification/PersonName/FirstName",
+		"SkillsPassport/LearnerInfo/Identification/PersonName/Surname",
+		"SkillsPassport/LearnerInfo/Identification/ContactInfo/Address/Contact/Municipality",
+		"SkillsPassport/LearnerInfo/Identification/ContactInfo/Address/Contact/Country/Label",
+		"SkillsPassport/LearnerInfo/Identification/ContactInfo/Email/Contact",
+		"SkillsPassport/LearnerInfo/Identification/ContactInfo/TelephoneList/Telephone/Use/Code",
+		"SkillsPassport/LearnerInfo/Identification/ContactInfo/WebsiteList/Website/Contact",
+		"SkillsPassport/LearnerInfo/Headline/Description/Label",
+		"SkillsPassport/LearnerInfo/WorkExperienceList/WorkExperience/Period/From",
+		"SkillsPassport/LearnerInfo/WorkExperienceList/WorkExperience/Period/Current",
+		"SkillsPassport/LearnerInfo/WorkExperienceList/WorkExperience/Period/To",
+		"SkillsPassport/LearnerInfo/WorkExperienceList/WorkExperience/Position/Label",
+		"SkillsPassport/LearnerInfo/WorkExperienceList/WorkExperience/Activities",
+		"SkillsPassport/LearnerInfo/WorkExperienceList/WorkExperience/Employer/Name",
+		"SkillsPassport/LearnerInfo/EducationList/Education/Title",
+		"SkillsPassport/LearnerInfo/EducationList/Education/Organisation/Name",
+		"SkillsPassport/LearnerInfo/Skills/Linguistic/MotherTongueList/MotherTongue/Description/Label",
+		"SkillsPassport/LearnerInfo/Skills/Linguistic/ForeignLanguageList/ForeignLanguage/ProficiencyLevel/Listening",
+		"SkillsPassport/LearnerInfo/Skills/Linguistic/ForeignLanguageList/ForeignLanguage/ProficiencyLevel/Writing",
+	} {
+		if !paths[want] {
+			t.Errorf("missing element %s", want)
+		}
+	}
+
+	var decoded SkillsPassport
+	if err := xml.Unmarshal(doc, &decoded); err != nil {
+		t.Fatalf("failed to decode generated document: %v", err)
+	}
+	if decoded.XMLName.Space != Namespace {
+		t.Errorf("namespace = %q, want %q", decoded.XMLName.Space, Namespace)
+	}
+	if decoded.DocumentInfo.CreationDate != "2025-01-02T03:04:05Z" {
+		t.Errorf("CreationDate = %q", decoded.DocumentInfo.CreationDate)
+	}
+
+	name := decoded.LearnerInfo.Identification.PersonName
+	if name.FirstName != "Jane Van" || name.Surname != "Doe" {
+		t.Errorf("PersonName = %+v", name)
+	}
+
+	work := decoded.LearnerInfo.WorkExperienceList
+	if len(work) != 2 {
+		t.Fatalf("got %d work experiences, want 2", len(work))
+	}
+	if from := work[0].Period.From; from == nil || from.Year != "2023" || from.Month != "--08" {
+		t.Errorf("first period From = %+v", from)
+	}
+	if !work[0].Period.Current || work[0].Period.To != nil {
+		t.Errorf("ongoing position should be current, got %+v", work[0].Period)
+	}
+	if to := work[1].Period.To; to == nil || to.Year != "2022" || to.Month != "--07" {
+		t.Errorf("second period To = %+v", to)
+	}
+	if address := work[1].Employer.ContactInfo.Address.Contact; address.Municipality != "" || address.Country.Label != "Ukraine" {
+		t.Errorf("single-part location should map to country, got %+v", address)
+	}
+
+	if from := decoded.LearnerInfo.EducationList[0].Period.From; from == nil || from.Year != "2014" || from.Month != "" {
+		t.Errorf("education period From = %+v", from)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	linguistic := FromResumeData(fixture(), time.Now()).LearnerInfo.Skills.Linguistic
+
+	if len(linguistic.MotherTongueList) != 1 || linguistic.MotherTongueList[0].Description.Label != "Ukrainian" {
+		t.Errorf("MotherTongueList = %+v", linguistic.MotherTongueList)
+	}
+
+	want := map[string]string{"English": "B1", "German": "A2", "Klingon": ""}
+	if len(linguistic.ForeignLanguageList) != len(want) {
+		t.Fatalf("got %d foreign languages, want %d", len(linguistic.ForeignLanguageList), len(want))
+	}
+	for _, lang := range linguistic.ForeignLanguageList {
+		level := ""
+		if lang.ProficiencyLevel != nil {
+			level = lang.ProficiencyLevel.Reading
+		}
+		if level != want[lang.Description.Label] {
+			t.Errorf("%s level = %q, want %q", lang.Description.Label, level, want[lang.Description.Label])
+		}
+	}
+}
+
+func TestCEFRLevel(t *testing.T) {
+	tests := map[string]string{
+		"Elementary":           "A2",
+		"Limited Working":      "B1",
+		"Professional Working": "B2",
+		"Full Professional":    "C1",
+		"Native":               "C2",
+		"c1":                   "C1",
+		"D1":                   "",
+		"Fluent-ish":           "",
+	}
+
+	for proficiency, want := range tests {
+		if got := CEFRLevel(proficiency); got != want {
+			t.Errorf("CEFRLevel(%q) = %q, want %q", proficiency, got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/europass"
+)
+
+// serveEuropass serves the resume as a Europass XML document for upload to the Europass portal
+func serveEuropass(resumeData *components.ResumeData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, err := europass.Marshal(europass.FromResumeData(*resumeData, time.Now()))
+		if err != nil {
+			http.Error(w, "Failed to generate Europass XML: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Disposition", "attachment; filename=europass.xml")
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(doc)))
+
+		_, err = w.Write(doc)
+		if err != nil {
+			http.Error(w, "Failed to write Europass XML to response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
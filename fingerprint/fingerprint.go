@@ -0,0 +1,192 @@
+// Package fingerprint names the static files after a hash of their content,
+// such as css/style.3f2a1b9c.css, so that browsers may cache them for good:
+// a changed file gets a new name, and the pages that link it a new link.
+package fingerprint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/compression"
+)
+
+// hashLength is the number of hex digits of the hash kept in the names
+const hashLength = 8
+
+// ImmutableCacheControl is sent with the fingerprinted files, whose content
+// never changes under the same name
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// file is a static file served under its own or its fingerprinted name
+type file struct {
+	data []byte
+	etag string
+	// immutable is set for the fingerprinted name
+	immutable bool
+	// encoded holds the compressed data by encoding, for the text files
+	encoded map[string][]byte
+}
+
+// Assets holds the static files under both their names
+type Assets struct {
+	// prefix is the URL path the files are served under, such as /static/
+	prefix string
+	// names maps the names of the files to their fingerprinted names
+	names map[string]string
+	files map[string]file
+	// reference matches the links to the files in a page or a stylesheet
+	reference *regexp.Regexp
+}
+
+// New fingerprints the files of fsys, which are served under the URL path
+// prefix. The references of the stylesheets to the other files, such as
+// their fonts, are rewritten first, so that their hash covers them.
+func New(fsys fs.FS, prefix string) (*Assets, error) {
+	a := &Assets{
+		prefix:    prefix,
+		names:     map[string]string{},
+		files:     map[string]file{},
+		reference: regexp.MustCompile(regexp.QuoteMeta(prefix) + `[\w./-]+`),
+	}
+
+	var stylesheets []string
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if path.Ext(name) == ".css" {
+			stylesheets = append(stylesheets, name)
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		return a.add(name, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range stylesheets {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.add(name, a.Rewrite(data)); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// add serves data under name and its fingerprinted name, compressed when
+// that makes it smaller
+func (a *Assets) add(name string, data []byte) error {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])[:hashLength]
+	ext := path.Ext(name)
+	hashed := strings.TrimSuffix(name, ext) + "." + hash + ext
+
+	encoded := map[string][]byte{}
+	if compression.Compressible(mime.TypeByExtension(ext)) {
+		for _, encoding := range compression.Encodings {
+			compressed, err := compression.Compress(encoding, data)
+			if err != nil {
+				return err
+			}
+			if len(compressed) < len(data) {
+				encoded[encoding] = compressed
+			}
+		}
+	}
+
+	a.names[name] = hashed
+	a.files[name] = file{data: data, etag: `"` + hash + `"`, encoded: encoded}
+	a.files[hashed] = file{data: data, etag: `"` + hash + `"`, immutable: true, encoded: encoded}
+	return nil
+}
+
+// Path returns the URL of the fingerprinted name of the file, or of the
+// file itself when it is unknown
+func (a *Assets) Path(name string) string {
+	if hashed, ok := a.names[name]; ok {
+		return a.prefix + hashed
+	}
+	return a.prefix + name
+}
+
+// Rewrite replaces the links to the files in src, such as
+// /static/css/style.css, with the links to their fingerprinted names. The
+// other links are left as they are.
+func (a *Assets) Rewrite(src []byte) []byte {
+	return a.reference.ReplaceAllFunc(src, func(link []byte) []byte {
+		return []byte(a.Path(string(link[len(a.prefix):])))
+	})
+}
+
+// Names returns the names of the files, their own and the fingerprinted
+// ones, in order
+func (a *Assets) Names() []string {
+	names := make([]string, 0, len(a.files))
+	for name := range a.files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ReadFile returns the content of the file served under name
+func (a *Assets) ReadFile(name string) ([]byte, bool) {
+	f, ok := a.files[name]
+	return f.data, ok
+}
+
+// Encoded returns the content of the file compressed with encoding, if it
+// is worth compressing
+func (a *Assets) Encoded(name, encoding string) ([]byte, bool) {
+	data, ok := a.files[name].encoded[encoding]
+	return data, ok
+}
+
+// ServeHTTP serves the file named by the path of the request, without the
+// prefix, compressed in the best encoding the request accepts. The
+// fingerprinted names are cached for a year; the others are revalidated on
+// each use by their ETag.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	f, ok := a.files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if f.immutable {
+		w.Header().Set("Cache-Control", ImmutableCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	data, etag := f.data, f.etag
+	if len(f.encoded) > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+		available := slices.DeleteFunc(slices.Clone(compression.Encodings), func(encoding string) bool {
+			return f.encoded[encoding] == nil
+		})
+		if encoding := compression.Negotiate(r, available...); encoding != "" {
+			// Each encoding is a different representation with its own tag
+			data, etag = f.encoded[encoding], strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`
+			w.Header().Set("Content-Encoding", encoding)
+		}
+	}
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+}
@@ -0,0 +1,113 @@
+package fingerprint
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testAssets(t *testing.T, font string) *Assets {
+	t.Helper()
+	assets, err := New(fstest.MapFS{
+		"css/style.css": {Data: []byte("@font-face { src: url('/static/fonts/a.ttf'); }")},
+		"fonts/a.ttf":   {Data: []byte(font)},
+		"js/main.js":    {Data: []byte("console.log(1)")},
+	}, "/static/")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return assets
+}
+
+func TestRewrite(t *testing.T) {
+	assets := testAssets(t, "font")
+
+	page := string(assets.Rewrite([]byte(`<link href="/static/css/style.css"/><script src="/static/js/main.js"></script><img src="/static/img/missing.png"/>`)))
+	if !regexp.MustCompile(`href="/static/css/style\.[0-9a-f]{8}\.css"`).MatchString(page) {
+		t.Errorf("Rewrite() did not fingerprint the stylesheet: %s", page)
+	}
+	if !regexp.MustCompile(`src="/static/js/main\.[0-9a-f]{8}\.js"`).MatchString(page) {
+		t.Errorf("Rewrite() did not fingerprint the script: %s", page)
+	}
+	if !strings.Contains(page, `src="/static/img/missing.png"`) {
+		t.Errorf("Rewrite() changed the link to an unknown file: %s", page)
+	}
+
+	css, _ := assets.ReadFile("css/style.css")
+	if !strings.Contains(string(css), assets.Path("fonts/a.ttf")) {
+		t.Errorf("the stylesheet does not link the fingerprinted font: %s", css)
+	}
+
+	// A changed font changes the name of the stylesheet that links it
+	if other := testAssets(t, "new font"); other.Path("css/style.css") == assets.Path("css/style.css") {
+		t.Error("the stylesheet kept its name when its font changed")
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	assets := testAssets(t, "font")
+	get := func(name string, header ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/"+name, nil)
+		if len(header) == 2 {
+			r.Header.Set(header[0], header[1])
+		}
+		rec := httptest.NewRecorder()
+		assets.ServeHTTP(rec, r)
+		return rec
+	}
+
+	hashed := strings.TrimPrefix(assets.Path("js/main.js"), "/static/")
+	rec := get(hashed)
+	if rec.Code != http.StatusOK || rec.Body.String() != "console.log(1)" {
+		t.Fatalf("GET %s = %d %q", hashed, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != ImmutableCacheControl {
+		t.Errorf("fingerprinted Cache-Control = %q, want %q", got, ImmutableCacheControl)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/javascript") {
+		t.Errorf("Content-Type = %q, want JavaScript", got)
+	}
+
+	rec = get("js/main.js")
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("GET js/main.js = %d with Cache-Control %q, want 200 with no-cache", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+	if rec := get("js/main.js", "If-None-Match", rec.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation = %d, want 304", rec.Code)
+	}
+
+	if rec := get("js/other.js"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET js/other.js = %d, want 404", rec.Code)
+	}
+}
+
+func TestServeHTTPEncoding(t *testing.T) {
+	script := strings.Repeat("console.log(1);\n", 100)
+	assets, err := New(fstest.MapFS{"js/main.js": {Data: []byte(script)}}, "/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/js/main.js", nil)
+	r.Header.Set("Accept-Encoding", "gzip, br")
+	rec := httptest.NewRecorder()
+	assets.ServeHTTP(rec, r)
+
+	brotli, _ := assets.Encoded("js/main.js", "br")
+	if rec.Header().Get("Content-Encoding") != "br" || !bytes.Equal(rec.Body.Bytes(), brotli) {
+		t.Errorf("GET js/main.js = %q in %q, want the brotli file", rec.Body.String(), rec.Header().Get("Content-Encoding"))
+	}
+	if len(brotli) == 0 || len(brotli) >= len(script) {
+		t.Errorf("brotli file has %d bytes, want fewer than %d", len(brotli), len(script))
+	}
+	if etag := rec.Header().Get("ETag"); !strings.HasSuffix(etag, `-br"`) {
+		t.Errorf("ETag = %s, want the tag of the brotli file", etag)
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/pdflayout"
+	"github.com/fairytale5571/cv_onopchenko/pdftext"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenCases are the fixture configs in testdata/golden rendered by the
+// golden tests; each writes <name>.html, <name>.pdf.txt and <name>.layout
+var goldenCases = []struct {
+	name, config, variant string
+}{
+	{"minimal", "minimal.yaml", ""},
+	{"full", "full.yaml", ""},
+	{"full-backend", "full.yaml", "backend"},
+	{"layout", "layout.yaml", ""},
+}
+
+func TestGoldenHTML(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := loadGolden(t, tc.config, tc.variant)
+			var page bytes.Buffer
+			if err := components.Resume(*data).Render(context.Background(), &page); err != nil {
+				t.Fatalf("render error = %v", err)
+			}
+			checkGolden(t, tc.name+".html", normalizeHTML(page.Bytes()))
+		})
+	}
+}
+
+func TestGoldenPDF(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pdf, err := renderPDF(loadGolden(t, tc.config, tc.variant))
+			if err != nil {
+				t.Fatalf("renderPDF() error = %v", err)
+			}
+			pages, err := pdftext.Pages(pdf)
+			if err != nil {
+				t.Fatalf("reading the PDF: %v", err)
+			}
+			checkGolden(t, tc.name+".pdf.txt", pdfText(pages))
+		})
+	}
+}
+
+// TestGoldenLayout catches layout shifts that keep the text intact: it
+// compares where each string of the PDF lands with a stored snapshot
+func TestGoldenLayout(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pdf, err := renderPDF(loadGolden(t, tc.config, tc.variant))
+			if err != nil {
+				t.Fatalf("renderPDF() error = %v", err)
+			}
+			got, err := pdflayout.Take(pdf)
+			if err != nil {
+				t.Fatalf("reading the PDF: %v", err)
+			}
+
+			path := filepath.Join("testdata", "golden", tc.name+".layout")
+			if *update {
+				if err := os.WriteFile(path, got.Marshal(), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			stored, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v; run go test -run %s -update to create it", err, t.Name())
+			}
+			want, err := pdflayout.Parse(stored)
+			if err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+
+			changes := pdflayout.Diff(want, got, pdflayout.DefaultTolerance)
+			if len(changes) == 0 {
+				return
+			}
+			const shown = 20
+			var report strings.Builder
+			for i, change := range changes {
+				if i == shown {
+					fmt.Fprintf(&report, "\n  ... and %d more", len(changes)-shown)
+					break
+				}
+				report.WriteString("\n  " + change.String())
+			}
+			t.Errorf("%s: %d layout changes:%s\nrun go test -run %s -update if the change is intended", path, len(changes), report.String(), t.Name())
+		})
+	}
+}
+
+func loadGolden(t *testing.T, config, variant string) *components.ResumeData {
+	t.Helper()
+	opts := options{configPath: filepath.Join("testdata", "golden", config), variant: variant, theme: components.ThemeSystem}
+	data, err := opts.loadValid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+var (
+	whitespace  = regexp.MustCompile(`\s+`)
+	betweenTags = regexp.MustCompile(`> ?<`)
+)
+
+// normalizeHTML collapses the whitespace of a page and puts every tag on a
+// line of its own, so that a change shows up as a few changed lines
+func normalizeHTML(page []byte) string {
+	s := whitespace.ReplaceAllString(string(page), " ")
+	s = betweenTags.ReplaceAllString(s, ">\n<")
+	return strings.TrimSpace(s) + "\n"
+}
+
+// pdfText lays out the page count and the strings of each page, one per line
+func pdfText(pages [][]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pages: %d\n", len(pages))
+	for i, page := range pages {
+		fmt.Fprintf(&b, "\n--- page %d\n", i+1)
+		for _, line := range page {
+			b.WriteString(strings.TrimRight(strings.ReplaceAll(line, "\n", `\n`), " ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// checkGolden compares got with testdata/golden/name, or rewrites the file
+// with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run go test -run %s -update to create it", err, t.Name())
+	}
+	if got == string(want) {
+		return
+	}
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(string(want), "\n")
+	for i := range max(len(gotLines), len(wantLines)) {
+		g, w := line(gotLines, i), line(wantLines, i)
+		if g != w {
+			t.Errorf("%s differs at line %d:\n got: %s\nwant: %s\nrun go test -run %s -update if the change is intended", path, i+1, g, w, t.Name())
+			return
+		}
+	}
+}
+
+func line(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return "<end of file>"
+}
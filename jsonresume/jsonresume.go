@@ -0,0 +1,432 @@
+// Package jsonresume converts resume data to and from the JSON Resume schema
+// (https://jsonresume.org/schema).
+package jsonresume
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+// Date layouts used by config.yaml and by JSON Resume (ISO 8601)
+const (
+	configDateLayout = "Jan 2006"
+	isoMonthLayout   = "2006-01"
+	isoDayLayout     = "2006-01-02"
+
+	// presentDate marks an ongoing position in config.yaml
+	presentDate = "Present"
+
+	networkLinkedIn = "LinkedIn"
+	networkGitHub   = "GitHub"
+
+	// JSON Resume has no sections for talks and open-source contributions;
+	// they are exported as projects of these types
+	projectTypeTalk         = "talk"
+	projectTypeContribution = "open source"
+)
+
+// Resume is the subset of the JSON Resume schema that maps onto ResumeData
+type Resume struct {
+	Basics       Basics        `json:"basics"`
+	Work         []Work        `json:"work,omitempty"`
+	Education    []Education   `json:"education,omitempty"`
+	Skills       []Skill       `json:"skills,omitempty"`
+	Languages    []Language    `json:"languages,omitempty"`
+	Projects     []Project     `json:"projects,omitempty"`
+	Publications []Publication `json:"publications,omitempty"`
+	Certificates []Certificate `json:"certificates,omitempty"`
+}
+
+// Basics holds the personal information of the candidate
+type Basics struct {
+	Name     string    `json:"name"`
+	Label    string    `json:"label,omitempty"`
+	Image    string    `json:"image,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	Phone    string    `json:"phone,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Summary  string    `json:"summary,omitempty"`
+	Location *Location `json:"location,omitempty"`
+	Profiles []Profile `json:"profiles,omitempty"`
+}
+
+// Location is the postal location of the candidate
+type Location struct {
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// Profile is a social network profile
+type Profile struct {
+	Network  string `json:"network"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url"`
+}
+
+// Work is a single position in the work history
+type Work struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+// Education is a single degree or course of study
+type Education struct {
+	Institution string `json:"institution"`
+	Area        string `json:"area,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+// Skill is a group of related keywords
+type Skill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// Language is a spoken language and its fluency
+type Language struct {
+	Language string `json:"language"`
+	Fluency  string `json:"fluency,omitempty"`
+}
+
+// Project is a single showcased project. Type tells talks and open-source
+// contributions from the projects of the config.
+type Project struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Entity      string   `json:"entity,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+}
+
+// Publication is an article, a paper or a book
+type Publication struct {
+	Name        string `json:"name"`
+	Publisher   string `json:"publisher,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+}
+
+// Certificate is a certificate or a completed training
+type Certificate struct {
+	Name   string `json:"name"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// FromResumeData converts resume data into the JSON Resume schema.
+// Fields without a JSON Resume counterpart (links of skills and technologies,
+// employment type, education location, certificate expiry and credential ID,
+// talk location, pull request counts) are dropped. Talks and open-source
+// contributions become projects of the "talk" and "open source" types.
+func FromResumeData(data components.ResumeData) Resume {
+	p := data.Personal
+	out := Resume{
+		Basics: Basics{
+			Name:     p.Name,
+			Label:    p.Title,
+			Image:    p.Photo,
+			Email:    p.Email,
+			Phone:    p.Phone,
+			URL:      p.Website,
+			Summary:  p.Summary,
+			Location: toLocation(p.Location),
+		},
+	}
+
+	if p.Linkedin != "" {
+		out.Basics.Profiles = append(out.Basics.Profiles, Profile{Network: networkLinkedIn, Username: username(p.Linkedin), URL: p.Linkedin})
+	}
+	if p.Github != "" {
+		out.Basics.Profiles = append(out.Basics.Profiles, Profile{Network: networkGitHub, Username: username(p.Github), URL: p.Github})
+	}
+
+	for _, exp := range data.Experience {
+		out.Work = append(out.Work, Work{
+			Name:       exp.Company,
+			Position:   exp.Position,
+			Location:   exp.Location,
+			StartDate:  toISODate(exp.StartDate),
+			EndDate:    toISODate(exp.EndDate),
+			Highlights: exp.Description,
+		})
+	}
+
+	for _, edu := range data.Education {
+		out.Education = append(out.Education, Education{
+			Institution: edu.Institution,
+			StudyType:   edu.Degree,
+			StartDate:   toISODate(edu.StartDate),
+			EndDate:     toISODate(edu.EndDate),
+		})
+	}
+
+	for _, skill := range data.Skills {
+		out.Skills = append(out.Skills, Skill{Name: skill.Category, Keywords: linkNames(skill.Items)})
+	}
+
+	for _, lang := range data.Languages {
+		out.Languages = append(out.Languages, Language{Language: lang.Language, Fluency: lang.Proficiency})
+	}
+
+	for _, project := range data.Projects {
+		out.Projects = append(out.Projects, Project{
+			Name:        project.Name,
+			Description: project.Description,
+			URL:         project.Link,
+			Keywords:    linkNames(project.Technologies),
+		})
+	}
+
+	for _, contribution := range data.Contributions {
+		project := Project{
+			Name:        contribution.Repo,
+			Description: contribution.Description,
+			URL:         contribution.URL,
+			Type:        projectTypeContribution,
+			StartDate:   toISODate(contribution.StartDate),
+			EndDate:     toISODate(contribution.EndDate),
+		}
+		if contribution.Role != "" {
+			project.Roles = []string{contribution.Role}
+		}
+		out.Projects = append(out.Projects, project)
+	}
+
+	for _, talk := range data.Talks {
+		out.Projects = append(out.Projects, Project{
+			Name:        talk.Title,
+			Description: talk.Summary,
+			URL:         talk.URL,
+			Entity:      talk.Event,
+			Type:        projectTypeTalk,
+			StartDate:   toISODate(talk.Date),
+		})
+	}
+
+	for _, publication := range data.Publications {
+		out.Publications = append(out.Publications, Publication{
+			Name:        publication.Title,
+			Publisher:   publication.Publisher,
+			ReleaseDate: toISODate(publication.Date),
+			URL:         publication.URL,
+			Summary:     publication.Summary,
+		})
+	}
+
+	for _, cert := range data.Certifications {
+		out.Certificates = append(out.Certificates, Certificate{
+			Name:   cert.Name,
+			Date:   toISODate(cert.Date),
+			Issuer: cert.Issuer,
+			URL:    cert.URL,
+		})
+	}
+
+	return out
+}
+
+// ToResumeData converts a JSON Resume document into the config.yaml layout
+func (r Resume) ToResumeData() components.ResumeData {
+	var data components.ResumeData
+
+	b := r.Basics
+	data.Personal = components.PersonalInfo{
+		Name:     b.Name,
+		Title:    b.Label,
+		Email:    b.Email,
+		Phone:    b.Phone,
+		Location: fromLocation(b.Location),
+		Summary:  b.Summary,
+		Photo:    b.Image,
+		Website:  b.URL,
+	}
+	for _, profile := range b.Profiles {
+		switch strings.ToLower(profile.Network) {
+		case strings.ToLower(networkLinkedIn):
+			data.Personal.Linkedin = profile.URL
+		case strings.ToLower(networkGitHub):
+			data.Personal.Github = profile.URL
+		}
+	}
+
+	for _, work := range r.Work {
+		description := work.Highlights
+		if len(description) == 0 && work.Summary != "" {
+			description = []string{work.Summary}
+		}
+		data.Experience = append(data.Experience, components.ExperienceItem{
+			Company:     work.Name,
+			Position:    work.Position,
+			Location:    work.Location,
+			StartDate:   fromISODate(work.StartDate),
+			EndDate:     fromISOEndDate(work.EndDate),
+			Description: description,
+		})
+	}
+
+	for _, edu := range r.Education {
+		degree := edu.StudyType
+		if edu.Area != "" {
+			degree = strings.TrimSpace(degree + " " + edu.Area)
+		}
+		data.Education = append(data.Education, components.EducationItem{
+			Institution: edu.Institution,
+			Degree:      degree,
+			StartDate:   fromISODate(edu.StartDate),
+			EndDate:     fromISOEndDate(edu.EndDate),
+		})
+	}
+
+	for _, skill := range r.Skills {
+		data.Skills = append(data.Skills, components.SkillCategory{Category: skill.Name, Items: toLinks(skill.Keywords)})
+	}
+
+	for _, lang := range r.Languages {
+		data.Languages = append(data.Languages, components.LanguageItem{Language: lang.Language, Proficiency: lang.Fluency})
+	}
+
+	for _, project := range r.Projects {
+		switch strings.ToLower(project.Type) {
+		case projectTypeContribution:
+			data.Contributions = append(data.Contributions, components.ContributionItem{
+				Repo:        project.Name,
+				Role:        strings.Join(project.Roles, ", "),
+				StartDate:   fromISODate(project.StartDate),
+				EndDate:     fromISODate(project.EndDate),
+				URL:         project.URL,
+				Description: project.Description,
+			})
+		case projectTypeTalk:
+			data.Talks = append(data.Talks, components.TalkItem{
+				Title:   project.Name,
+				Event:   project.Entity,
+				Date:    fromISODate(project.StartDate),
+				URL:     project.URL,
+				Summary: project.Description,
+			})
+		default:
+			data.Projects = append(data.Projects, components.ProjectItem{
+				Name:         project.Name,
+				Description:  project.Description,
+				Technologies: toLinks(project.Keywords),
+				Link:         project.URL,
+			})
+		}
+	}
+
+	for _, publication := range r.Publications {
+		data.Publications = append(data.Publications, components.PublicationItem{
+			Title:     publication.Name,
+			Publisher: publication.Publisher,
+			Date:      fromISODate(publication.ReleaseDate),
+			URL:       publication.URL,
+			Summary:   publication.Summary,
+		})
+	}
+
+	for _, cert := range r.Certificates {
+		data.Certifications = append(data.Certifications, components.CertificationItem{
+			Name:   cert.Name,
+			Issuer: cert.Issuer,
+			Date:   fromISODate(cert.Date),
+			URL:    cert.URL,
+		})
+	}
+
+	return data
+}
+
+// toLocation splits "City, Region" into its JSON Resume parts
+func toLocation(location string) *Location {
+	if location == "" {
+		return nil
+	}
+	city, region, _ := strings.Cut(location, ", ")
+	return &Location{City: city, Region: region}
+}
+
+// fromLocation joins the JSON Resume location parts back into a single line
+func fromLocation(location *Location) string {
+	if location == nil {
+		return ""
+	}
+	var parts []string
+	for _, part := range []string{location.Address, location.City, location.Region, location.CountryCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// toISODate converts "Aug 2023" into "2023-08". "Present" becomes an empty
+// date, and values in an unknown format are passed through unchanged.
+func toISODate(date string) string {
+	if date == "" || strings.EqualFold(date, presentDate) {
+		return ""
+	}
+	t, err := time.Parse(configDateLayout, date)
+	if err != nil {
+		return date
+	}
+	return t.Format(isoMonthLayout)
+}
+
+// fromISODate converts an ISO 8601 date into the "Aug 2023" form used in config.yaml
+func fromISODate(date string) string {
+	if t, err := time.Parse(isoMonthLayout, date); err == nil {
+		return t.Format(configDateLayout)
+	}
+	if t, err := time.Parse(isoDayLayout, date); err == nil {
+		return t.Format(configDateLayout)
+	}
+	return date
+}
+
+// fromISOEndDate is fromISODate that treats a missing end date as "Present"
+func fromISOEndDate(date string) string {
+	if date == "" {
+		return presentDate
+	}
+	return fromISODate(date)
+}
+
+// username extracts the last path segment of a profile URL
+func username(profileURL string) string {
+	trimmed := strings.TrimRight(profileURL, "/")
+	return trimmed[strings.LastIndex(trimmed, "/")+1:]
+}
+
+func linkNames(links []components.Link) []string {
+	var names []string
+	for _, link := range links {
+		names = append(names, link.Name)
+	}
+	return names
+}
+
+func toLinks(names []string) []components.Link {
+	var links []components.Link
+	for _, name := range names {
+		links = append(links, components.Link{Name: name})
+	}
+	return links
+}
@@ -0,0 +1,177 @@
+package jsonresume
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"gopkg.in/yaml.v3"
+)
+
+// sharedFixture only uses fields that exist in both models, so it must
+// survive a round trip unchanged.
+const sharedFixture = `
+personal:
+  name: "Jane Doe"
+  title: "Golang Developer"
+  email: "jane@example.com"
+  phone: "+380000000000"
+  location: "Kyiv, Ukraine"
+  linkedin: "https://www.linkedin.com/in/jane"
+  github: "https://github.com/jane"
+  summary: "Backend developer."
+  photo: "https://example.com/jane.jpg"
+  website: "https://jane.example.com"
+skills:
+  - category: "Languages"
+    items:
+      - name: "Go"
+      - name: "SQL"
+experience:
+  - company: "ACME"
+    position: "Software Developer"
+    location: "Kyiv, Ukraine"
+    startDate: "Aug 2023"
+    endDate: "Present"
+    description:
+      - "Built microservices."
+      - "Mentored juniors."
+  - company: "Initech"
+    position: "Intern"
+    location: "Odesa, Ukraine"
+    startDate: "Sep 2021"
+    endDate: "Jul 2022"
+    description:
+      - "Wrote tests."
+education:
+  - institution: "KPI"
+    degree: "Bachelor"
+    startDate: "Sep 2014"
+    endDate: "Jun 2018"
+projects:
+  - name: "cv"
+    description: "This resume."
+    technologies:
+      - name: "Go"
+      - name: "templ"
+    link: "https://github.com/jane/cv"
+contributions:
+  - repo: "golang/go"
+    role: "Contributor"
+    startDate: "Jan 2022"
+    url: "https://github.com/golang/go"
+    description: "Fixes in net/http."
+publications:
+  - title: "Profiling Go services"
+    publisher: "Habr"
+    date: "Jun 2023"
+    url: "https://habr.com/ru/articles/1"
+    summary: "pprof in production."
+talks:
+  - title: "Go at scale"
+    event: "GopherCon"
+    date: "Oct 2023"
+    url: "https://example.com/talk"
+    summary: "Lessons learned."
+certifications:
+  - name: "Certified Kubernetes Application Developer"
+    issuer: "The Linux Foundation"
+    date: "Mar 2023"
+    url: "https://example.com/ckad/123"
+languages:
+  - language: "English"
+    proficiency: "Professional Working"
+  - language: "Ukrainian"
+    proficiency: "Native"
+`
+
+func loadFixture(t *testing.T) components.ResumeData {
+	t.Helper()
+
+	var data components.ResumeData
+	if err := yaml.Unmarshal([]byte(sharedFixture), &data); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return data
+}
+
+func TestRoundTripFromResumeData(t *testing.T) {
+	want := loadFixture(t)
+
+	encoded, err := json.Marshal(FromResumeData(want))
+	if err != nil {
+		t.Fatalf("failed to encode JSON Resume: %v", err)
+	}
+
+	var decoded Resume
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode JSON Resume: %v", err)
+	}
+
+	got := decoded.ToResumeData()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip lost data\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestRoundTripFromJSONResume(t *testing.T) {
+	want := Resume{
+		Basics: Basics{
+			Name:     "Jane Doe",
+			Label:    "Golang Developer",
+			Email:    "jane@example.com",
+			Location: &Location{City: "Kyiv", Region: "Ukraine"},
+			Profiles: []Profile{
+				{Network: "LinkedIn", Username: "jane", URL: "https://www.linkedin.com/in/jane"},
+				{Network: "GitHub", Username: "jane", URL: "https://github.com/jane"},
+			},
+		},
+		Work: []Work{
+			{Name: "ACME", Position: "Developer", StartDate: "2023-08", Highlights: []string{"Built things."}},
+		},
+		Education: []Education{
+			{Institution: "KPI", StudyType: "Bachelor", StartDate: "2014-09", EndDate: "2018-06"},
+		},
+		Skills:    []Skill{{Name: "Databases", Keywords: []string{"Postgres", "Redis"}}},
+		Languages: []Language{{Language: "English", Fluency: "Native"}},
+		Projects: []Project{
+			{Name: "cv", URL: "https://github.com/jane/cv", Keywords: []string{"Go"}},
+			{Name: "golang/go", Type: "open source", Roles: []string{"Maintainer"}, StartDate: "2021-02", EndDate: "2023-05"},
+			{Name: "templ", Type: "open source", StartDate: "2021"},
+			{Name: "Go at scale", Entity: "GopherCon", Type: "talk", StartDate: "2023-10", URL: "https://example.com/talk"},
+		},
+		Publications: []Publication{
+			{Name: "Profiling Go services", Publisher: "Habr", ReleaseDate: "2023-06", URL: "https://habr.com/ru/articles/1"},
+		},
+		Certificates: []Certificate{
+			{Name: "AWS Certified Developer", Date: "2024-05", Issuer: "Amazon Web Services", URL: "https://example.com/aws/1"},
+		},
+	}
+
+	got := FromResumeData(want.ToResumeData())
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip lost data\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestDateConversion(t *testing.T) {
+	tests := []struct {
+		config string
+		iso    string
+	}{
+		{config: "Aug 2023", iso: "2023-08"},
+		{config: "Present", iso: ""},
+		{config: "2019", iso: "2019"},
+	}
+
+	for _, tt := range tests {
+		if got := toISODate(tt.config); got != tt.iso {
+			t.Errorf("toISODate(%q) = %q, want %q", tt.config, got, tt.iso)
+		}
+	}
+
+	if got := fromISODate("2021-03-15"); got != "Mar 2021" {
+		t.Errorf("fromISODate(%q) = %q, want %q", "2021-03-15", got, "Mar 2021")
+	}
+}
@@ -0,0 +1,85 @@
+package linkcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a passed link is trusted without a new check
+const DefaultCacheTTL = 24 * time.Hour
+
+// Cache keeps the results of earlier runs in a JSON file. Only the links
+// that passed are reused, so that the dead ones are checked on every run
+// until they are fixed.
+type Cache struct {
+	path string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	results map[string]Result
+}
+
+// OpenCache reads the cache file at path; a missing file is an empty cache
+func OpenCache(path string, ttl time.Duration) (*Cache, error) {
+	c := &Cache{path: path, ttl: ttl, results: map[string]Result{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading link cache: %w", err)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("error parsing link cache %s: %w", path, err)
+	}
+	for _, result := range results {
+		c.results[result.URL] = result
+	}
+	return c, nil
+}
+
+// get returns the result of link if it passed within the TTL
+func (c *Cache) get(link string, now time.Time) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.results[link]
+	if !ok || result.Status == Dead || now.Sub(result.CheckedAt) > c.ttl {
+		return Result{}, false
+	}
+	result.Cached = true
+	return result, true
+}
+
+func (c *Cache) put(result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[result.URL] = result
+}
+
+// Save writes the cache back to its file, ordered by URL
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	results := make([]Result, 0, len(c.results))
+	for _, result := range c.results {
+		results = append(results, result)
+	}
+	c.mu.Unlock()
+	slices.SortFunc(results, func(a, b Result) int { return strings.Compare(a.URL, b.URL) })
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing link cache: %w", err)
+	}
+	return nil
+}
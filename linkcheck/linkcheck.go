@@ -0,0 +1,292 @@
+// Package linkcheck finds the dead and redirected links of the resume: the
+// skill, technology, project, contribution, publication, talk and
+// certification links of the config and the links of the rendered page.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+// Status is the verdict on a link
+type Status string
+
+const (
+	// OK links answer with a success status without redirects
+	OK Status = "ok"
+	// Redirected links work but lead elsewhere, so the config should use
+	// the address they end up at
+	Redirected Status = "redirected"
+	// Dead links end with an error status or do not answer
+	Dead Status = "dead"
+)
+
+// Defaults of the checker
+const (
+	DefaultConcurrency = 8
+	DefaultRetries     = 2
+	DefaultBackoff     = time.Second
+	DefaultTimeout     = 10 * time.Second
+
+	// maxRedirects is how many redirects are followed before giving up
+	maxRedirects = 10
+
+	// userAgent is sent because some sites refuse the default one of Go
+	userAgent = "Mozilla/5.0 (compatible; cv-check-links)"
+)
+
+// Result is the outcome of checking a link
+type Result struct {
+	URL    string `json:"url"`
+	Status Status `json:"status"`
+	// Code is the HTTP status of the last response, 0 when there was none
+	Code int `json:"code,omitempty"`
+	// Location is where a redirected link ends up
+	Location string `json:"location,omitempty"`
+	// Error tells why a dead link gave no response
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Cached is set when the result comes from an earlier run
+	Cached bool `json:"-"`
+}
+
+// Collect returns the http(s) links of the resume model in order, without
+// duplicates
+func Collect(data components.ResumeData) []string {
+	p := data.Personal
+	links := []string{p.Linkedin, p.Github, p.Website, p.Photo}
+	for _, category := range data.Skills {
+		for _, item := range category.Items {
+			links = append(links, item.Link)
+		}
+	}
+	for _, exp := range data.Experience {
+		for _, tech := range exp.Technologies {
+			links = append(links, tech.Link)
+		}
+	}
+	for _, project := range data.Projects {
+		links = append(links, project.Link)
+		for _, tech := range project.Technologies {
+			links = append(links, tech.Link)
+		}
+	}
+	for _, contribution := range data.Contributions {
+		links = append(links, contribution.URL)
+	}
+	for _, publication := range data.Publications {
+		links = append(links, publication.URL)
+	}
+	for _, talk := range data.Talks {
+		links = append(links, talk.URL)
+	}
+	for _, cert := range data.Certifications {
+		links = append(links, cert.URL)
+	}
+	return Merge(links)
+}
+
+// attribute matches the absolute links of href and src attributes
+var attribute = regexp.MustCompile(`(?:href|src)="(https?://[^"]+)"`)
+
+// Extract returns the absolute http(s) links of a rendered page in order,
+// without duplicates
+func Extract(page []byte) []string {
+	var links []string
+	for _, match := range attribute.FindAllSubmatch(page, -1) {
+		links = append(links, html.UnescapeString(string(match[1])))
+	}
+	return Merge(links)
+}
+
+// Merge joins lists of links, keeping the first of duplicates and only the
+// http(s) links
+func Merge(lists ...[]string) []string {
+	var links []string
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, link := range list {
+			if seen[link] || !(strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://")) {
+				continue
+			}
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// Checker checks links concurrently, retrying failures that may pass
+type Checker struct {
+	// Client sends the requests; its redirect policy is replaced, so that
+	// redirects are seen. Nil uses a client with DefaultTimeout.
+	Client *http.Client
+	// Concurrency is how many links are checked at once
+	Concurrency int
+	// Retries is how many times a link that did not answer or answered
+	// with a server error is checked again
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each next one
+	Backoff time.Duration
+	// Cache, when set, skips the links that passed in an earlier run
+	Cache *Cache
+}
+
+// NewChecker returns a checker with the default settings
+func NewChecker() *Checker {
+	return &Checker{
+		Concurrency: DefaultConcurrency,
+		Retries:     DefaultRetries,
+		Backoff:     DefaultBackoff,
+	}
+}
+
+// Check checks links and returns their results in the same order
+func (c *Checker) Check(ctx context.Context, links []string) []Result {
+	client := &http.Client{Timeout: DefaultTimeout}
+	if c.Client != nil {
+		copied := *c.Client
+		client = &copied
+	}
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	results := make([]Result, len(links))
+	limit := make(chan struct{}, max(c.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			results[i] = c.check(ctx, client, link)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// check checks a link, retrying failures that may pass
+func (c *Checker) check(ctx context.Context, client *http.Client, link string) Result {
+	if c.Cache != nil {
+		if result, ok := c.Cache.get(link, time.Now()); ok {
+			return result
+		}
+	}
+
+	backoff := c.Backoff
+	result, retry := follow(ctx, client, link)
+	for attempt := 0; retry && attempt < c.Retries; attempt++ {
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		result, retry = follow(ctx, client, link)
+	}
+
+	result.CheckedAt = time.Now()
+	if c.Cache != nil {
+		c.Cache.put(result)
+	}
+	return result
+}
+
+// follow follows the redirects of link to the final response. It reports
+// whether a failure may pass on a retry.
+func follow(ctx context.Context, client *http.Client, link string) (Result, bool) {
+	result := Result{URL: link}
+	current := link
+	for range maxRedirects + 1 {
+		code, location, err := request(ctx, client, current)
+		if err != nil {
+			result.Status, result.Error = Dead, err.Error()
+			return result, true
+		}
+		result.Code = code
+
+		if code >= 300 && code < 400 && location != "" {
+			next, err := url.Parse(current)
+			if err == nil {
+				next, err = next.Parse(location)
+			}
+			if err != nil {
+				result.Status, result.Error = Dead, fmt.Sprintf("invalid redirect to %q", location)
+				return result, false
+			}
+			current = next.String()
+			continue
+		}
+
+		switch {
+		case code >= 400:
+			result.Status = Dead
+			return result, code >= 500 || code == http.StatusTooManyRequests
+		case current != link:
+			result.Status, result.Location = Redirected, current
+		default:
+			result.Status = OK
+		}
+		return result, false
+	}
+	result.Status, result.Error = Dead, "too many redirects"
+	return result, false
+}
+
+// request asks for link with HEAD, and with GET when the site does not
+// answer HEAD well. It returns the status and the redirect location.
+func request(ctx context.Context, client *http.Client, link string) (int, string, error) {
+	var code int
+	var location string
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, link, nil)
+		if err != nil {
+			return 0, "", err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		resp, err := client.Do(req)
+		if err != nil {
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return 0, "", err
+		}
+		// Only the status matters, a bit of the body keeps the connection
+		io.CopyN(io.Discard, resp.Body, 64<<10)
+		resp.Body.Close()
+
+		code, location = resp.StatusCode, resp.Header.Get("Location")
+		if code < 400 {
+			break
+		}
+	}
+	return code, location, nil
+}
+
+// Problems returns the dead and redirected results, the dead ones first
+func Problems(results []Result) []Result {
+	var problems []Result
+	for _, status := range []Status{Dead, Redirected} {
+		for _, result := range results {
+			if result.Status == status {
+				problems = append(problems, result)
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,159 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func TestCollect(t *testing.T) {
+	var data components.ResumeData
+	data.Personal.Github = "https://github.com/jane"
+	data.Personal.Photo = "/static/img/profile.jpg"
+	data.Skills = []components.SkillCategory{{Items: []components.Link{{Name: "Go", Link: "https://go.dev/"}, {Name: "SQL"}}}}
+	data.Experience = []components.ExperienceItem{{Technologies: []components.Link{{Name: "Go", Link: "https://go.dev/"}}}}
+	data.Projects = []components.ProjectItem{{Link: "http://example.com/project"}}
+	data.Contributions = []components.ContributionItem{{URL: "https://github.com/golang/go"}}
+	data.Publications = []components.PublicationItem{{URL: "https://example.com/article"}}
+	data.Talks = []components.TalkItem{{URL: "https://example.com/article"}}
+	data.Certifications = []components.CertificationItem{{URL: "https://example.com/verify/1"}}
+
+	want := []string{"https://github.com/jane", "https://go.dev/", "http://example.com/project", "https://github.com/golang/go", "https://example.com/article", "https://example.com/verify/1"}
+	if got := Collect(data); !slices.Equal(got, want) {
+		t.Errorf("Collect() = %q, want %q", got, want)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	page := []byte(`<a href="https://go.dev/?a=1&amp;b=2">Go</a><img src="https://example.com/a.png"><a href="mailto:jane@example.com"></a><link href="/static/css/style.css">`)
+	want := []string{"https://go.dev/?a=1&b=2", "https://example.com/a.png"}
+	if got := Extract(page); !slices.Equal(got, want) {
+		t.Errorf("Extract() = %q, want %q", got, want)
+	}
+}
+
+// testServer answers like the sites the resume links to
+func testServer(t *testing.T, flaky *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if flaky.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheck(t *testing.T) {
+	var flaky atomic.Int32
+	server := testServer(t, &flaky)
+	checker := &Checker{Client: server.Client(), Concurrency: 2, Retries: 2, Backoff: time.Millisecond}
+
+	links := []string{"/ok", "/moved", "/missing", "/loop", "/no-head", "/flaky"}
+	for i, link := range links {
+		links[i] = server.URL + link
+	}
+	results := checker.Check(context.Background(), links)
+
+	want := []struct {
+		status Status
+		code   int
+	}{
+		{OK, http.StatusOK},
+		{Redirected, http.StatusOK},
+		{Dead, http.StatusNotFound},
+		{Dead, http.StatusFound},
+		{OK, http.StatusOK},
+		{OK, http.StatusOK},
+	}
+	for i, result := range results {
+		if result.URL != links[i] || result.Status != want[i].status || result.Code != want[i].code {
+			t.Errorf("result %d = %+v, want %s %d", i, result, want[i].status, want[i].code)
+		}
+	}
+	if results[1].Location != server.URL+"/ok" {
+		t.Errorf("redirect location = %q, want %s/ok", results[1].Location, server.URL)
+	}
+	if results[3].Error != "too many redirects" {
+		t.Errorf("redirect loop error = %q", results[3].Error)
+	}
+
+	problems := Problems(results)
+	if len(problems) != 3 || problems[0].Status != Dead || problems[2].Status != Redirected {
+		t.Errorf("Problems() = %+v, want the dead links before the redirected one", problems)
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	link := server.URL + "/gone"
+	server.Close()
+
+	checker := &Checker{Retries: 1, Backoff: time.Millisecond}
+	result := checker.Check(context.Background(), []string{link})[0]
+	if result.Status != Dead || result.Code != 0 || result.Error == "" {
+		t.Errorf("unreachable link = %+v, want dead with an error", result)
+	}
+}
+
+func TestCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	links := []string{server.URL + "/ok", server.URL + "/missing"}
+	path := filepath.Join(t.TempDir(), "links-cache.json")
+
+	for run := range 2 {
+		cache, err := OpenCache(path, time.Hour)
+		if err != nil {
+			t.Fatalf("OpenCache() error = %v", err)
+		}
+		checker := &Checker{Client: server.Client(), Cache: cache}
+		results := checker.Check(context.Background(), links)
+		if err := cache.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if results[0].Cached != (run == 1) || results[1].Cached {
+			t.Errorf("run %d: cached = %v, %v; want only the passed link from the second run", run, results[0].Cached, results[1].Cached)
+		}
+	}
+
+	// /ok is requested once, /missing on both runs with HEAD and GET
+	if got := requests.Load(); got != 5 {
+		t.Errorf("requests = %d, want 5", got)
+	}
+
+	cache, err := OpenCache(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.get(links[0], time.Now()); ok {
+		t.Error("an expired result is reused")
+	}
+}
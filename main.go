@@ -1,8 +1,6 @@
 package main
 
 import (
-	"bytes"
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +10,7 @@ import (
 
 	"github.com/fairytale5571/cv_onopchenko/components"
 	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/code"
 	"github.com/johnfercher/maroto/v2/pkg/components/col"
 	"github.com/johnfercher/maroto/v2/pkg/components/row"
 	"github.com/johnfercher/maroto/v2/pkg/components/text"
@@ -19,8 +18,6 @@ import (
 	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
 	"github.com/johnfercher/maroto/v2/pkg/core"
 	"github.com/johnfercher/maroto/v2/pkg/props"
-	"github.com/jung-kurt/gofpdf"
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -50,8 +47,9 @@ const (
 	// Default port
 	DefaultPort = "8081"
 
-	// File paths
-	ConfigPath = "config.yaml"
+	// Default paths, overridable with the -config and -out flags
+	DefaultConfigPath = "config.yaml"
+	DefaultOutputDir  = "dist"
 )
 
 // Colors for the document - Modern color scheme matching website
@@ -66,124 +64,53 @@ var (
 	AccentColor      = props.Color{Red: 55, Green: 48, Blue: 163}   // Darker indigo for accents
 )
 
-// ResumeData represents the structure of the resume data
-type ResumeData struct {
-	Personal struct {
-		Name     string `yaml:"name"`
-		Title    string `yaml:"title"`
-		Email    string `yaml:"email"`
-		Phone    string `yaml:"phone"`
-		Location string `yaml:"location"`
-		Linkedin string `yaml:"linkedin"`
-		Github   string `yaml:"github"`
-		Summary  string `yaml:"summary"`
-	} `yaml:"personal"`
-	Skills []struct {
-		Category string `yaml:"category"`
-		Items    []struct {
-			Name string `yaml:"name"`
-			Link string `yaml:"link,omitempty"`
-		} `yaml:"items"`
-	} `yaml:"skills"`
-	Experience []struct {
-		Company        string   `yaml:"company"`
-		Position       string   `yaml:"position"`
-		Location       string   `yaml:"location"`
-		StartDate      string   `yaml:"startDate"`
-		EndDate        string   `yaml:"endDate"`
-		Description    []string `yaml:"description"`
-		EmploymentType string   `yaml:"employmentType"`
-		Technologies   []struct {
-			Name string `yaml:"name"`
-			Link string `yaml:"link,omitempty"`
-		} `yaml:"technologies"`
-	} `yaml:"experience"`
-	Education []struct {
-		Institution string `yaml:"institution"`
-		Degree      string `yaml:"degree"`
-		Location    string `yaml:"location"`
-		StartDate   string `yaml:"startDate"`
-		EndDate     string `yaml:"endDate"`
-	} `yaml:"education"`
-	Projects []struct {
-		Name         string `yaml:"name"`
-		Description  string `yaml:"description"`
-		Technologies []struct {
-			Name string `yaml:"name"`
-			Link string `yaml:"link,omitempty"`
-		} `yaml:"technologies"`
-		Link string `yaml:"link"`
-	} `yaml:"projects"`
-	Languages []struct {
-		Language    string `yaml:"language"`
-		Proficiency string `yaml:"proficiency"`
-	} `yaml:"languages"`
-}
-
-// loadResumeData loads the resume data from the config file
-func loadResumeData(configPath string) (*components.ResumeData, error) {
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
-	}
-
-	var resumeData components.ResumeData
-	err = yaml.Unmarshal(data, &resumeData)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing config file: %w", err)
-	}
-
-	return &resumeData, nil
-}
-
 func main() {
-	// Load resume data
-	resumeData, err := loadResumeData(ConfigPath)
-	if err != nil {
-		log.Fatalf("Failed to load resume data: %v", err)
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
 	}
+}
 
-	// Create resume component
-	resumeComponent := components.Resume(*resumeData)
-
-	// Serve static files
-	fs := http.FileServer(http.Dir("static"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
+// genMarotoPdf serves the resume as a PDF download, generated once and cached
+func genMarotoPdf(resumeData *components.ResumeData, cache *renderCache, m *serverMetrics) http.HandlerFunc {
+	render := m.timePDF(variantLabel(resumeData), func() ([]byte, error) {
+		return renderPDF(resumeData)
+	})
 
-	// Define routes
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		err := resumeComponent.Render(context.Background(), w)
+	return func(w http.ResponseWriter, r *http.Request) {
+		pdf, err := cache.get(render)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, "Failed to generate PDF: "+err.Error(), http.StatusInternalServerError)
+			return
 		}
-	})
 
-	// Export PDF endpoint using the new Maroto library
-	http.Handle("/export-pdf", genMarotoPdf(resumeData))
+		// Set response headers for PDF download
+		fileName := fmt.Sprintf("resume_%s.pdf", time.Now().Format("20060102_150405"))
+		w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdf)))
 
-	// Start the server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = DefaultPort
+		// Write PDF to response
+		_, err = w.Write(pdf)
+		if err != nil {
+			http.Error(w, "Failed to write PDF to response: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
-
-	log.Printf("Starting server on http://localhost:%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
-// genMarotoPdf generates a PDF using the Maroto v2 library
-func genMarotoPdf(resumeData *components.ResumeData) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Создаем gofpdf для регистрации шрифта
-		pdf := gofpdf.New(PDFOrientation, PDFUnit, PDFSize, "")
-		// Регистрируем шрифт Montserrat-Medium
-		// Примечание: он не будет использоваться ниже из-за ограничений Maroto
-		pdf.AddFont("montserrat", "", "static/fonts/Montserrat-Medium.ttf")
-
-		// Create a new maroto instance
-		m := maroto.New()
+// renderPDF generates the resume PDF using the Maroto v2 library
+func renderPDF(resumeData *components.ResumeData) ([]byte, error) {
+	// Create a new maroto instance
+	m := maroto.New()
 
-		// Header section with name/title (reduced height)
+	// Header section with name/title, optionally with a QR code on the right
+	if resumeData.QRCode.PDF {
+		qrContent, err := qrCodeContent(resumeData, "")
+		if err != nil {
+			return nil, fmt.Errorf("error building QR code: %w", err)
+		}
+		addQRHeader(m, resumeData.Personal.Name, resumeData.Personal.Title, qrContent)
+	} else {
 		headerRow := row.New(16)
 		headerCol := col.New(12)
 
@@ -212,259 +139,305 @@ func genMarotoPdf(resumeData *components.ResumeData) http.HandlerFunc {
 		titleCol.Add(titleComponent)
 		titleRow.Add(titleCol)
 		m.AddRows(titleRow)
+	}
 
-		// Add spacing
-		m.AddRows(row.New(10))
+	// Add spacing
+	m.AddRows(row.New(10))
 
-		// Contact Information Section with modern styling
-		sectionTitleRow := row.New(12) // Slightly taller row for better spacing
-		sectionTitleCol := col.New(12)
-		contactTitleComponent := text.New("Contact Information", props.Text{
-			Size:  FontSizeHeading,
-			Style: fontstyle.Bold,
-			Align: align.Left,
-			Color: &HeaderBgColor, // Use the vibrant blue color for consistency
-			Top:   2, // Add a small top padding
-		})
-		sectionTitleCol.Add(contactTitleComponent)
-		sectionTitleRow.Add(sectionTitleCol)
-		m.AddRows(sectionTitleRow)
-
- 	// Add a modern styled divider
- 	dividerRow := row.New(2) // Slightly taller for better visibility
- 	dividerCol := col.New(12)
- 	dividerText := strings.Repeat("━", 60) // Thicker Unicode horizontal line for modern look
- 	dividerComponent := text.New(dividerText, props.Text{
- 		Size:  FontSizeSmall,
- 		Align: align.Left,
- 		Color: &HeaderBgColor, // Match section title color
- 		Top:   0, // No top padding
- 	})
- 	dividerCol.Add(dividerComponent)
- 	dividerRow.Add(dividerCol)
- 	m.AddRows(dividerRow)
- 	m.AddRows(row.New(2)) // Add minimal space after divider
-
-		// Contact details
-		addContactInfo(m, "Email:", resumeData.Personal.Email)
-		addContactInfo(m, "Phone:", resumeData.Personal.Phone)
-		addContactInfo(m, "Location:", resumeData.Personal.Location)
-		addContactInfo(m, "LinkedIn:", resumeData.Personal.Linkedin)
-		addContactInfo(m, "GitHub:", resumeData.Personal.Github)
-
-		// Add spacing (reduced)
-		m.AddRows(row.New(8))
-
-		// Experience section with modern styling (reduced height)
-		experienceTitleRow := row.New(10)
-		experienceTitleCol := col.New(12)
-		experienceTitleComponent := text.New("Experience", props.Text{
-			Size:  FontSizeHeading,
-			Style: fontstyle.Bold,
-			Align: align.Left,
-			Color: &HeaderBgColor, // Use vibrant blue for section title
-			Top:   2, // Add a small top padding
-		})
-		experienceTitleCol.Add(experienceTitleComponent)
-		experienceTitleRow.Add(experienceTitleCol)
-		m.AddRows(experienceTitleRow)
-
-		// Add a modern styled divider under the section title
-		dividerRow = row.New(2)
-		dividerCol = col.New(12)
-		dividerText = strings.Repeat("━", 40) // Thicker Unicode horizontal line
-		dividerComponent = text.New(dividerText, props.Text{
-			Size:  FontSizeSmall,
-			Align: align.Left,
-			Color: &HeaderBgColor, // Match section title color
-		})
-		dividerCol.Add(dividerComponent)
-		dividerRow.Add(dividerCol)
-		m.AddRows(dividerRow)
-		m.AddRows(row.New(2)) // Add minimal space after divider
-
-		// Add subtitle with modern styling
-		subtitleRow := row.New(6)
-		subtitleCol := col.New(12)
-		subtitleComponent := text.New("Professional work history", props.Text{
-			Size:  FontSizeNormal,
-			Style: fontstyle.Italic,
-			Align: align.Left,
-			Color: &AccentColor, // Use accent color for subtitles
-			Top:   1, // Add a small top padding
-		})
-		subtitleCol.Add(subtitleComponent)
-		subtitleRow.Add(subtitleCol)
-		m.AddRows(subtitleRow)
+	// Contact Information Section with modern styling
+	sectionTitleRow := row.New(12) // Slightly taller row for better spacing
+	sectionTitleCol := col.New(12)
+	contactTitleComponent := text.New("Contact Information", props.Text{
+		Size:  FontSizeHeading,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Use the vibrant blue color for consistency
+		Top:   2,              // Add a small top padding
+	})
+	sectionTitleCol.Add(contactTitleComponent)
+	sectionTitleRow.Add(sectionTitleCol)
+	m.AddRows(sectionTitleRow)
 
-		m.AddRows(row.New(5))
+	// Add a modern styled divider
+	dividerRow := row.New(2) // Slightly taller for better visibility
+	dividerCol := col.New(12)
+	dividerText := strings.Repeat("━", 60) // Thicker Unicode horizontal line for modern look
+	dividerComponent := text.New(dividerText, props.Text{
+		Size:  FontSizeSmall,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Match section title color
+		Top:   0,              // No top padding
+	})
+	dividerCol.Add(dividerComponent)
+	dividerRow.Add(dividerCol)
+	m.AddRows(dividerRow)
+	m.AddRows(row.New(2)) // Add minimal space after divider
 
-		// Add each experience
-		for _, exp := range resumeData.Experience {
-			addExperienceItem(m, exp.Position, exp.Company, exp.StartDate+" - "+exp.EndDate, exp.Location, exp.EmploymentType, strings.Join(exp.Description, "\n"))
+	// Contact details
+	addContactInfo(m, "Email:", resumeData.Personal.Email)
+	addContactInfo(m, "Phone:", resumeData.Personal.Phone)
+	addContactInfo(m, "Location:", resumeData.Personal.Location)
+	addContactInfo(m, "LinkedIn:", resumeData.Personal.Linkedin)
+	addContactInfo(m, "GitHub:", resumeData.Personal.Github)
+
+	// Body sections in the order of layout.sections
+	for _, section := range components.Sections(*resumeData) {
+		switch section {
+		case components.SectionSkills:
+			addSkills(m, resumeData.Skills)
+		case components.SectionExperience:
+			addExperience(m, resumeData.Experience)
+		case components.SectionEducation:
+			addEducation(m, resumeData.Education)
+		case components.SectionProjects:
+			addProjects(m, resumeData.Projects)
+		case components.SectionContributions:
+			addContributions(m, resumeData.Contributions)
+		case components.SectionPublications:
+			addPublications(m, resumeData.Publications)
+		case components.SectionTalks:
+			addTalks(m, resumeData.Talks)
+		case components.SectionCertifications:
+			addCertifications(m, resumeData.Certifications)
+		case components.SectionLanguages:
+			addLanguages(m, resumeData.Languages)
 		}
+	}
 
-		// Education section if available
-		if len(resumeData.Education) > 0 {
- 		// Education section with modern styling (reduced spacing)
- 		m.AddRows(row.New(8))
- 		educationTitleRow := row.New(10)
-			educationTitleCol := col.New(12)
-			educationTitleComponent := text.New("Education", props.Text{
-				Size:  FontSizeHeading,
-				Style: fontstyle.Bold,
-				Align: align.Left,
-				Color: &HeaderBgColor, // Use vibrant blue for section title
-				Top:   2, // Add a small top padding
-			})
-			educationTitleCol.Add(educationTitleComponent)
-			educationTitleRow.Add(educationTitleCol)
-			m.AddRows(educationTitleRow)
-
-			// Add a modern styled divider under the section title
-			dividerRow = row.New(2)
-			dividerCol = col.New(12)
-			dividerText = strings.Repeat("━", 40) // Thicker Unicode horizontal line
-			dividerComponent = text.New(dividerText, props.Text{
-				Size:  FontSizeSmall,
-				Align: align.Left,
-				Color: &HeaderBgColor, // Match section title color
-			})
-			dividerCol.Add(dividerComponent)
-			dividerRow.Add(dividerCol)
-			m.AddRows(dividerRow)
-			m.AddRows(row.New(2)) // Add minimal space after divider
-
-			// Add subtitle for education section with modern styling
-			subtitleRow = row.New(6)
-			subtitleCol = col.New(12)
-			subtitleComponent = text.New("Academic background and qualifications", props.Text{
-				Size:  FontSizeNormal,
-				Style: fontstyle.Italic,
-				Align: align.Left,
-				Color: &AccentColor, // Use accent color for subtitles
-				Top:   1, // Add a small top padding
-			})
-			subtitleCol.Add(subtitleComponent)
-			subtitleRow.Add(subtitleCol)
-			m.AddRows(subtitleRow)
+	doc, err := m.Generate()
+	if err != nil {
+		return nil, err
+	}
+	return doc.GetBytes(), nil
+}
 
-			m.AddRows(row.New(5))
+// addSectionTitle adds the heading of a body section with a divider and a
+// subtitle
+func addSectionTitle(m core.Maroto, title, subtitle string) {
+	// Add spacing between sections (reduced)
+	m.AddRows(row.New(8))
 
-			// Add each education
-			for _, edu := range resumeData.Education {
-				addEducationItem(m, edu.Degree, edu.Institution, edu.StartDate+" - "+edu.EndDate, edu.Location)
-			}
-		}
+	// Section title with modern styling (reduced height)
+	titleRow := row.New(10)
+	titleCol := col.New(12)
+	titleComponent := text.New(title, props.Text{
+		Size:  FontSizeHeading,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Use vibrant blue for section title
+		Top:   2,              // Add a small top padding
+	})
+	titleCol.Add(titleComponent)
+	titleRow.Add(titleCol)
+	m.AddRows(titleRow)
 
-		// Skills section with modern styling (reduced spacing)
-		m.AddRows(row.New(8))
-		skillsTitleRow := row.New(10)
-		skillsTitleCol := col.New(12)
-		skillsTitleComponent := text.New("Skills", props.Text{
-			Size:  FontSizeHeading,
-			Style: fontstyle.Bold,
-			Align: align.Left,
-			Color: &HeaderBgColor, // Use vibrant blue for section title
-			Top:   2, // Add a small top padding
-		})
-		skillsTitleCol.Add(skillsTitleComponent)
-		skillsTitleRow.Add(skillsTitleCol)
-		m.AddRows(skillsTitleRow)
-
-		// Add a modern styled divider under the section title
-		dividerRow = row.New(2)
-		dividerCol = col.New(12)
-		dividerText = strings.Repeat("━", 40) // Thicker Unicode horizontal line
-		dividerComponent = text.New(dividerText, props.Text{
-			Size:  FontSizeSmall,
-			Align: align.Left,
-			Color: &HeaderBgColor, // Match section title color
-		})
-		dividerCol.Add(dividerComponent)
-		dividerRow.Add(dividerCol)
-		m.AddRows(dividerRow)
-		m.AddRows(row.New(2)) // Add minimal space after divider
+	// Add a modern styled divider under the section title
+	dividerRow := row.New(2)
+	dividerCol := col.New(12)
+	dividerText := strings.Repeat("━", 40) // Thicker Unicode horizontal line
+	dividerComponent := text.New(dividerText, props.Text{
+		Size:  FontSizeSmall,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Match section title color
+	})
+	dividerCol.Add(dividerComponent)
+	dividerRow.Add(dividerCol)
+	m.AddRows(dividerRow)
+	m.AddRows(row.New(2)) // Add minimal space after divider
 
-		// Add subtitle for skills section with modern styling
-		subtitleRow = row.New(6)
-		subtitleCol = col.New(12)
-		subtitleComponent = text.New("Technical competencies and expertise", props.Text{
-			Size:  FontSizeNormal,
-			Style: fontstyle.Italic,
-			Align: align.Left,
-			Color: &AccentColor, // Use accent color for subtitles
-			Top:   1, // Add a small top padding
-		})
-		subtitleCol.Add(subtitleComponent)
-		subtitleRow.Add(subtitleCol)
-		m.AddRows(subtitleRow)
+	// Add subtitle with modern styling
+	subtitleRow := row.New(6)
+	subtitleCol := col.New(12)
+	subtitleComponent := text.New(subtitle, props.Text{
+		Size:  FontSizeNormal,
+		Style: fontstyle.Italic,
+		Align: align.Left,
+		Color: &AccentColor, // Use accent color for subtitles
+		Top:   1,            // Add a small top padding
+	})
+	subtitleCol.Add(subtitleComponent)
+	subtitleRow.Add(subtitleCol)
+	m.AddRows(subtitleRow)
 
-		m.AddRows(row.New(5))
+	m.AddRows(row.New(5))
+}
 
-		// Add skills section
-		addSkillsSection(m, resumeData.Skills)
+// addExperience adds the experience section if there are positions
+func addExperience(m core.Maroto, experience []components.ExperienceItem) {
+	if len(experience) == 0 {
+		return
+	}
+	addSectionTitle(m, "Experience", "Professional work history")
 
-		// Languages section if available
-		if len(resumeData.Languages) > 0 {
-			langTitleRow := row.New(10)
-			langTitleCol := col.New(12)
-			langTitleComponent := text.New("Languages", props.Text{
-				Size:  FontSizeHeading,
-				Style: fontstyle.Bold,
-				Align: align.Left,
-				Color: &PrimaryColor,
-			})
-			langTitleCol.Add(langTitleComponent)
-			langTitleRow.Add(langTitleCol)
-			m.AddRows(langTitleRow)
-
-			// Languages list
-			langsText := ""
-			for i, lang := range resumeData.Languages {
-				langsText += lang.Language + " (" + lang.Proficiency + ")"
-				if i < len(resumeData.Languages)-1 {
-					langsText += ", "
-				}
-			}
+	// Add each experience
+	for _, exp := range experience {
+		addExperienceItem(m, exp.Position, exp.Company, exp.StartDate+" - "+exp.EndDate, exp.Location, exp.EmploymentType, strings.Join(exp.Description, "\n"))
+	}
+}
 
-			langsRow := row.New(6)
-			langsCol := col.New(12)
-			langsComponent := text.New(langsText, props.Text{
-				Size:  FontSizeNormal,
-				Style: fontstyle.Normal,
-				Align: align.Left,
-				Color: &PrimaryColor,
-			})
-			langsCol.Add(langsComponent)
-			langsRow.Add(langsCol)
-			m.AddRows(langsRow)
-		}
+// addEducation adds the education section if there are degrees
+func addEducation(m core.Maroto, education []components.EducationItem) {
+	if len(education) == 0 {
+		return
+	}
+	addSectionTitle(m, "Education", "Academic background and qualifications")
 
-		// Generate PDF
-		fileName := fmt.Sprintf("resume_%s.pdf", time.Now().Format("20060102_150405"))
-		doc, err := m.Generate()
-		if err != nil {
-			http.Error(w, "Failed to generate PDF: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+	// Add each education
+	for _, edu := range education {
+		addEducationItem(m, edu.Degree, edu.Institution, edu.StartDate+" - "+edu.EndDate, edu.Location)
+	}
+}
 
-		// Set response headers for PDF download
-		w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
-		w.Header().Set("Content-Type", "application/pdf")
-		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(doc.GetBytes())))
+// addSkills adds the skills section if there are skill categories
+func addSkills(m core.Maroto, skills []components.SkillCategory) {
+	if len(skills) == 0 {
+		return
+	}
+	addSectionTitle(m, "Skills", "Technical competencies and expertise")
+	addSkillsSection(m, skills)
+}
 
-		// Write PDF to response
-		_, err = w.Write(doc.GetBytes())
-		if err != nil {
-			http.Error(w, "Failed to write PDF to response: "+err.Error(), http.StatusInternalServerError)
-			return
+// addProjects adds the projects section if there are projects
+func addProjects(m core.Maroto, projects []components.ProjectItem) {
+	if len(projects) == 0 {
+		return
+	}
+	addSectionTitle(m, "Projects", "Selected projects")
+
+	for _, project := range projects {
+		addProjectItem(m, project)
+	}
+}
+
+// addContributions adds the open-source contributions section if there are
+// any
+func addContributions(m core.Maroto, contributions []components.ContributionItem) {
+	if len(contributions) == 0 {
+		return
+	}
+	addSectionTitle(m, "Open Source", "Contributions to open-source projects")
+
+	for _, contribution := range contributions {
+		addDatedItem(m, contribution.Repo, components.Period(contribution.StartDate, contribution.EndDate),
+			components.ContributionSummary(contribution), contribution.Description, contribution.URL)
+	}
+}
+
+// addPublications adds the publications section if there are any
+func addPublications(m core.Maroto, publications []components.PublicationItem) {
+	if len(publications) == 0 {
+		return
+	}
+	addSectionTitle(m, "Publications", "Articles and papers")
+
+	for _, publication := range publications {
+		addDatedItem(m, publication.Title, publication.Date, publication.Publisher, publication.Summary, publication.URL)
+	}
+}
+
+// addTalks adds the talks section if there are any
+func addTalks(m core.Maroto, talks []components.TalkItem) {
+	if len(talks) == 0 {
+		return
+	}
+	addSectionTitle(m, "Talks", "Conference and meetup talks")
+
+	for _, talk := range talks {
+		addDatedItem(m, talk.Title, talk.Date, components.TalkVenue(talk), talk.Summary, talk.URL)
+	}
+}
+
+// addCertifications adds the certifications section if there are any
+func addCertifications(m core.Maroto, certifications []components.CertificationItem) {
+	if len(certifications) == 0 {
+		return
+	}
+	addSectionTitle(m, "Certifications", "Certificates and trainings")
+
+	for _, cert := range certifications {
+		addCertificationItem(m, cert)
+	}
+}
+
+// addLanguages adds the languages as a single line if there are any
+func addLanguages(m core.Maroto, languages []components.LanguageItem) {
+	if len(languages) == 0 {
+		return
+	}
+
+	// Add spacing between sections (reduced)
+	m.AddRows(row.New(8))
+
+	langTitleRow := row.New(10)
+	langTitleCol := col.New(12)
+	langTitleComponent := text.New("Languages", props.Text{
+		Size:  FontSizeHeading,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &PrimaryColor,
+	})
+	langTitleCol.Add(langTitleComponent)
+	langTitleRow.Add(langTitleCol)
+	m.AddRows(langTitleRow)
+
+	// Languages list
+	langsText := ""
+	for i, lang := range languages {
+		langsText += lang.Language + " (" + lang.Proficiency + ")"
+		if i < len(languages)-1 {
+			langsText += ", "
 		}
 	}
+
+	langsRow := row.New(6)
+	langsCol := col.New(12)
+	langsComponent := text.New(langsText, props.Text{
+		Size:  FontSizeNormal,
+		Style: fontstyle.Normal,
+		Align: align.Left,
+		Color: &PrimaryColor,
+	})
+	langsCol.Add(langsComponent)
+	langsRow.Add(langsCol)
+	m.AddRows(langsRow)
+}
+
+// addQRHeader adds the name and title next to a QR code
+func addQRHeader(m core.Maroto, name, title, qrContent string) {
+	headerRow := row.New(30)
+
+	nameCol := col.New(9)
+	nameCol.Add(text.New(name, props.Text{
+		Top:   4,
+		Size:  FontSizeTitle + 2,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &HeaderBgColor,
+	}))
+	nameCol.Add(text.New(title, props.Text{
+		Top:   18,
+		Size:  FontSizeSubtitle,
+		Style: fontstyle.Normal,
+		Align: align.Left,
+		Color: &LinkColor,
+	}))
+
+	qrCol := code.NewQrCol(3, qrContent, props.Rect{
+		Center:  true,
+		Percent: 100,
+	})
+
+	headerRow.Add(nameCol, qrCol)
+	m.AddRows(headerRow)
 }
 
 // addContactInfo adds a contact information row with modern styling
 func addContactInfo(m core.Maroto, label, value string) {
+	// Fields left empty or marked private are skipped
+	if value == "" {
+		return
+	}
+
 	contactRow := row.New(7) // Slightly taller row for better spacing
 
 	// Create label column (3 units wide)
@@ -704,14 +677,246 @@ func addEducationItem(m core.Maroto, degree, institution, period, location strin
 	m.AddRows(separatorRow)
 }
 
+// addProjectItem adds a single project with its description, link and
+// technologies
+func addProjectItem(m core.Maroto, project components.ProjectItem) {
+	// Add minimal spacing before each project
+	m.AddRows(row.New(2))
+
+	nameRow := row.New(8)
+	nameCol := col.New(12)
+	nameCol.Add(text.New(project.Name, props.Text{
+		Top:   1,
+		Size:  FontSizeSubheading,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Use vibrant blue for project name
+	}))
+	nameRow.Add(nameCol)
+	m.AddRows(nameRow)
+
+	if project.Link != "" {
+		linkRow := row.New(6)
+		linkCol := col.New(12)
+		linkCol.Add(text.New(project.Link, props.Text{
+			Top:   1,
+			Size:  FontSizeNormal,
+			Style: fontstyle.Italic,
+			Align: align.Left,
+			Color: &LinkColor,
+		}))
+		linkRow.Add(linkCol)
+		m.AddRows(linkRow)
+	}
+
+	if description := strings.TrimSpace(project.Description); description != "" {
+		// The row grows with the lines the description wraps into
+		descriptionRow := row.New()
+		descriptionCol := col.New(12)
+		descriptionCol.Add(text.New(description, props.Text{
+			Top:   1.5,
+			Size:  FontSizeNormal,
+			Style: fontstyle.Normal,
+			Align: align.Left,
+			Color: &PrimaryColor,
+		}))
+		descriptionRow.Add(descriptionCol)
+		m.AddRows(descriptionRow)
+	}
+
+	if len(project.Technologies) > 0 {
+		var names []string
+		for _, tech := range project.Technologies {
+			names = append(names, tech.Name)
+		}
+		techRow := row.New(6)
+		techCol := col.New(12)
+		techCol.Add(text.New("Technologies: "+strings.Join(names, ", "), props.Text{
+			Top:   1,
+			Size:  FontSizeSmall + 1,
+			Style: fontstyle.Normal,
+			Align: align.Left,
+			Color: &GrayColor,
+		}))
+		techRow.Add(techCol)
+		m.AddRows(techRow)
+	}
+
+	// Add a subtle separator after each project
+	separatorRow := row.New(4)
+	separatorCol := col.New(12)
+	separatorCol.Add(text.New("· · ·", props.Text{
+		Size:  FontSizeSmall,
+		Align: align.Center,
+		Color: &GrayColor, // Gray for subtle appearance
+		Style: fontstyle.Normal,
+	}))
+	separatorRow.Add(separatorCol)
+	m.AddRows(separatorRow)
+}
+
+// addDatedItem adds a single contribution, publication or talk: its title with
+// the date on the right, a subtitle, a description and a link. Empty parts
+// are left out.
+func addDatedItem(m core.Maroto, title, date, subtitle, description, link string) {
+	// Add minimal spacing before each item
+	m.AddRows(row.New(2))
+
+	// Title with the date on the right, as for education
+	titleRow := row.New(8)
+	titleCol := col.New(8)
+	titleCol.Add(text.New(title, props.Text{
+		Top:   1,
+		Size:  FontSizeSubheading,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Use vibrant blue for the title
+	}))
+	dateCol := col.New(4)
+	dateCol.Add(text.New(date, props.Text{
+		Top:   1,
+		Size:  FontSizeNormal,
+		Style: fontstyle.Normal,
+		Align: align.Right,
+		Color: &GrayColor, // Gray for dates
+	}))
+	titleRow.Add(titleCol, dateCol)
+	m.AddRows(titleRow)
+
+	if subtitle != "" {
+		subtitleRow := row.New(7)
+		subtitleCol := col.New(12)
+		subtitleCol.Add(text.New(subtitle, props.Text{
+			Top:   1,
+			Size:  FontSizeNormal,
+			Style: fontstyle.Bold,
+			Align: align.Left,
+			Color: &PrimaryColor, // Dark color for the publisher, event or role
+		}))
+		subtitleRow.Add(subtitleCol)
+		m.AddRows(subtitleRow)
+	}
+
+	if description = strings.TrimSpace(description); description != "" {
+		// The row grows with the lines the description wraps into
+		descriptionRow := row.New()
+		descriptionCol := col.New(12)
+		descriptionCol.Add(text.New(description, props.Text{
+			Top:   1.5,
+			Size:  FontSizeNormal,
+			Style: fontstyle.Normal,
+			Align: align.Left,
+			Color: &PrimaryColor,
+		}))
+		descriptionRow.Add(descriptionCol)
+		m.AddRows(descriptionRow)
+	}
+
+	if link != "" {
+		linkRow := row.New(6)
+		linkCol := col.New(12)
+		linkCol.Add(text.New(link, props.Text{
+			Top:   1,
+			Size:  FontSizeNormal,
+			Style: fontstyle.Italic,
+			Align: align.Left,
+			Color: &LinkColor,
+		}))
+		linkRow.Add(linkCol)
+		m.AddRows(linkRow)
+	}
+
+	// Add a subtle separator after each item
+	separatorRow := row.New(4)
+	separatorCol := col.New(12)
+	separatorCol.Add(text.New("· · ·", props.Text{
+		Size:  FontSizeSmall,
+		Align: align.Center,
+		Color: &GrayColor, // Gray for subtle appearance
+		Style: fontstyle.Normal,
+	}))
+	separatorRow.Add(separatorCol)
+	m.AddRows(separatorRow)
+}
+
+// addCertificationItem adds a single certification with its issuer, dates and
+// credential
+func addCertificationItem(m core.Maroto, cert components.CertificationItem) {
+	// Add minimal spacing before each certification
+	m.AddRows(row.New(2))
+
+	// Name with the dates on the right, as for education
+	nameRow := row.New(8)
+	nameCol := col.New(8)
+	nameCol.Add(text.New(cert.Name, props.Text{
+		Top:   1,
+		Size:  FontSizeSubheading,
+		Style: fontstyle.Bold,
+		Align: align.Left,
+		Color: &HeaderBgColor, // Use vibrant blue for certification name
+	}))
+	datesCol := col.New(4)
+	datesCol.Add(text.New(components.CertificationDates(cert), props.Text{
+		Top:   1,
+		Size:  FontSizeNormal,
+		Style: fontstyle.Normal,
+		Align: align.Right,
+		Color: &GrayColor, // Gray for dates
+	}))
+	nameRow.Add(nameCol, datesCol)
+	m.AddRows(nameRow)
+
+	if cert.Issuer != "" {
+		issuerRow := row.New(7)
+		issuerCol := col.New(12)
+		issuerCol.Add(text.New(cert.Issuer, props.Text{
+			Top:   1,
+			Size:  FontSizeNormal,
+			Style: fontstyle.Bold,
+			Align: align.Left,
+			Color: &PrimaryColor, // Dark color for the issuer
+		}))
+		issuerRow.Add(issuerCol)
+		m.AddRows(issuerRow)
+	}
+
+	// Credential ID and verification link on one line
+	var credential []string
+	if cert.CredentialID != "" {
+		credential = append(credential, "Credential ID: "+cert.CredentialID)
+	}
+	if cert.URL != "" {
+		credential = append(credential, cert.URL)
+	}
+	if len(credential) > 0 {
+		credentialRow := row.New(6)
+		credentialCol := col.New(12)
+		credentialCol.Add(text.New(strings.Join(credential, "   "), props.Text{
+			Top:   1,
+			Size:  FontSizeSmall + 1,
+			Style: fontstyle.Normal,
+			Align: align.Left,
+			Color: &GrayColor,
+		}))
+		credentialRow.Add(credentialCol)
+		m.AddRows(credentialRow)
+	}
+
+	// Add a subtle separator after each certification
+	separatorRow := row.New(4)
+	separatorCol := col.New(12)
+	separatorCol.Add(text.New("· · ·", props.Text{
+		Size:  FontSizeSmall,
+		Align: align.Center,
+		Color: &GrayColor, // Gray for subtle appearance
+		Style: fontstyle.Normal,
+	}))
+	separatorRow.Add(separatorCol)
+	m.AddRows(separatorRow)
+}
+
 // addSkillsSection adds the skills section with categories and modern styling
-func addSkillsSection(m core.Maroto, skills []struct {
-	Category string `yaml:"category"`
-	Items    []struct {
-		Name string `yaml:"name"`
-		Link string `yaml:"link,omitempty"`
-	} `yaml:"items"`
-}) {
+func addSkillsSection(m core.Maroto, skills []components.SkillCategory) {
 	// Process each category
 	for i, category := range skills {
 		// Add minimal spacing before categories (except the first one)
@@ -834,202 +1039,3 @@ func ensureTextFitsInColumn(text string, maxCharsPerLine int) string {
 
 	return result.String()
 }
-
-// addLanguageSection adds the languages section with modern styling
-func addLanguageSection(m core.Maroto, languages []struct {
-	Language    string `yaml:"language"`
-	Proficiency string `yaml:"proficiency"`
-}) {
-	if len(languages) == 0 {
-		return
-	}
-
-	// Add minimal spacing before languages section
-	m.AddRows(row.New(3))
-
-	// Create language section title with modern styling
-	languageRow := row.New(10)
-	languageCol := col.New(12)
-	languageComponent := text.New("Languages", props.Text{
-		Size:  FontSizeHeading,
-		Style: fontstyle.Bold,
-		Align: align.Left,
-		Color: &HeaderBgColor, // Use vibrant blue for section title
-		Top:   2, // Add a small top padding
-	})
-	languageCol.Add(languageComponent)
-	languageRow.Add(languageCol)
-	m.AddRows(languageRow)
-
-	// Add a divider under the section title
-	dividerRow := row.New(2)
-	dividerCol := col.New(12)
-	dividerText := strings.Repeat("━", 30) // Medium-length divider for visual separation
-	dividerComponent := text.New(dividerText, props.Text{
-		Size:  FontSizeSmall,
-		Align: align.Left,
-		Color: &HeaderBgColor, // Match section title color
-	})
-	dividerCol.Add(dividerComponent)
-	dividerRow.Add(dividerCol)
-	m.AddRows(dividerRow)
-	m.AddRows(row.New(2)) // Add minimal space after divider
-
-	// Create a more visually appealing language list
-	// Display each language on its own row with a bullet point
-	for _, lang := range languages {
-		langRow := row.New(7)
-		langCol := col.New(12)
-
-		// Format as "• Language — Proficiency" with an em dash for better visual separation
-		langText := "• " + lang.Language + " — " + lang.Proficiency
-
-		langComponent := text.New(langText, props.Text{
-			Size:  FontSizeNormal,
-			Style: fontstyle.Normal,
-			Align: align.Left,
-			Color: &PrimaryColor, // Dark color for better readability
-		})
-		langCol.Add(langComponent)
-		langRow.Add(langCol)
-		m.AddRows(langRow)
-	}
-
-	// Add minimal space after languages section
-	m.AddRows(row.New(3))
-}
-
-// generatePDF generates a PDF from the resume data
-func generatePDF(resumeData ResumeData) (*bytes.Buffer, error) {
-	// Create PDF
-	m := maroto.New()
-
-	// Add page header
-	headerRow := row.New(32)
-
-	// Name and title column
-	nameCol := col.New(8)
-	nameComponent := text.New(resumeData.Personal.Name, props.Text{
-		Size:  FontSizeTitle,
-		Style: fontstyle.Bold,
-		Align: align.Left,
-		Color: &PrimaryColor,
-		Top:   3,
-	})
-	nameCol.Add(nameComponent)
-
-	titleComponent := text.New(resumeData.Personal.Title, props.Text{
-		Size:  FontSizeSubheading,
-		Style: fontstyle.Italic,
-		Align: align.Left,
-		Color: &GrayColor,
-		Top:   12,
-	})
-	nameCol.Add(titleComponent)
-
-	// Summary if available
-	if resumeData.Personal.Summary != "" {
-		summaryComponent := text.New(resumeData.Personal.Summary, props.Text{
-			Size:  FontSizeNormal,
-			Style: fontstyle.Normal,
-			Align: align.Left,
-			Top:   20,
-		})
-		nameCol.Add(summaryComponent)
-	}
-
-	// Profile picture if available
-	imgCol := col.New(4)
-	// Here you would add a profile picture if available
-
-	headerRow.Add(nameCol, imgCol)
-	m.AddRows(headerRow)
-
-	// Add contact information
-	// Add each contact method
-	addContactInfo(m, "Email:", resumeData.Personal.Email)
-	addContactInfo(m, "Phone:", resumeData.Personal.Phone)
-	addContactInfo(m, "Location:", resumeData.Personal.Location)
-	if resumeData.Personal.Linkedin != "" {
-		addContactInfo(m, "LinkedIn:", resumeData.Personal.Linkedin)
-	}
-	if resumeData.Personal.Github != "" {
-		addContactInfo(m, "GitHub:", resumeData.Personal.Github)
-	}
-
-	// Add minimal spacing
-	m.AddRows(row.New(5))
-
-	// Experience section
-	if len(resumeData.Experience) > 0 {
-		// Add section title
-		expTitleRow := row.New(8)
-		expTitleCol := col.New(12)
-		expTitleComponent := text.New("Experience", props.Text{
-			Size:  FontSizeHeading,
-			Style: fontstyle.Bold,
-			Align: align.Left,
-			Color: &PrimaryColor,
-		})
-		expTitleCol.Add(expTitleComponent)
-		expTitleRow.Add(expTitleCol)
-		m.AddRows(expTitleRow)
-
-		// Add each experience item
-		for _, exp := range resumeData.Experience {
-			addExperienceItem(m, exp.Position, exp.Company, exp.StartDate+" - "+exp.EndDate, exp.Location, exp.EmploymentType, strings.Join(exp.Description, "\n"))
-		}
-	}
-
-	// Education section
-	if len(resumeData.Education) > 0 {
-		// Add section title
-		eduTitleRow := row.New(8)
-		eduTitleCol := col.New(12)
-		eduTitleComponent := text.New("Education", props.Text{
-			Size:  FontSizeHeading,
-			Style: fontstyle.Bold,
-			Align: align.Left,
-			Color: &PrimaryColor,
-		})
-		eduTitleCol.Add(eduTitleComponent)
-		eduTitleRow.Add(eduTitleCol)
-		m.AddRows(eduTitleRow)
-
-		// Add each education item
-		for _, edu := range resumeData.Education {
-			addEducationItem(m, edu.Degree, edu.Institution, edu.StartDate+" - "+edu.EndDate, edu.Location)
-		}
-	}
-
-	// Skills section
-	if len(resumeData.Skills) > 0 {
-		// Add section title
-		skillsTitleRow := row.New(8)
-		skillsTitleCol := col.New(12)
-		skillsTitleComponent := text.New("Skills", props.Text{
-			Size:  FontSizeHeading,
-			Style: fontstyle.Bold,
-			Align: align.Left,
-			Color: &PrimaryColor,
-		})
-		skillsTitleCol.Add(skillsTitleComponent)
-		skillsTitleRow.Add(skillsTitleCol)
-		m.AddRows(skillsTitleRow)
-
-		// Add skills section
-		addSkillsSection(m, resumeData.Skills)
-	}
-
-	// Languages section if available
-	if len(resumeData.Languages) > 0 {
-		addLanguageSection(m, resumeData.Languages)
-	}
-
-	pdfBuffer, err := m.Generate()
-	if err != nil {
-		return nil, err
-	}
-
-	return bytes.NewBuffer(pdfBuffer.GetBytes()), nil
-}
@@ -0,0 +1,241 @@
+// Package markdown renders the resume as a Markdown document
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+// ContentType is the MIME type of the rendered document
+const ContentType = "text/markdown; charset=utf-8"
+
+// Render writes the resume as Markdown, in the same section order as the page
+func Render(data components.ResumeData) []byte {
+	var b bytes.Buffer
+	p := data.Personal
+
+	fmt.Fprintf(&b, "# %s\n\n", escape(p.Name))
+	if p.Title != "" {
+		fmt.Fprintf(&b, "**%s**\n\n", escape(p.Title))
+	}
+
+	var contacts []string
+	if p.Email != "" {
+		contacts = append(contacts, link(p.Email, "mailto:"+p.Email))
+	}
+	for _, value := range []string{p.Phone, p.Location} {
+		if value != "" {
+			contacts = append(contacts, escape(value))
+		}
+	}
+	for _, profile := range []struct{ name, url string }{
+		{"LinkedIn", p.Linkedin},
+		{"GitHub", p.Github},
+		{"Website", p.Website},
+	} {
+		if profile.url != "" {
+			contacts = append(contacts, link(profile.name, profile.url))
+		}
+	}
+	if len(contacts) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(contacts, " · "))
+	}
+
+	if p.Summary != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", escape(strings.TrimSpace(p.Summary)))
+	}
+
+	for _, section := range components.Sections(data) {
+		switch section {
+		case components.SectionSkills:
+			if len(data.Skills) > 0 {
+				b.WriteString("## Skills\n\n")
+				for _, category := range data.Skills {
+					fmt.Fprintf(&b, "- **%s:** %s\n", escape(category.Category), links(category.Items))
+				}
+				b.WriteString("\n")
+			}
+		case components.SectionExperience:
+			if len(data.Experience) > 0 {
+				b.WriteString("## Experience\n\n")
+				for _, exp := range data.Experience {
+					fmt.Fprintf(&b, "### %s — %s\n\n", escape(exp.Position), escape(exp.Company))
+					if d := details(period(exp.StartDate, exp.EndDate), exp.Location, exp.EmploymentType); d != "" {
+						fmt.Fprintf(&b, "*%s*\n\n", escape(d))
+					}
+					for _, desc := range exp.Description {
+						fmt.Fprintf(&b, "- %s\n", escape(desc))
+					}
+					if len(exp.Description) > 0 {
+						b.WriteString("\n")
+					}
+					if len(exp.Technologies) > 0 {
+						fmt.Fprintf(&b, "**Technologies:** %s\n\n", links(exp.Technologies))
+					}
+				}
+			}
+		case components.SectionEducation:
+			if len(data.Education) > 0 {
+				b.WriteString("## Education\n\n")
+				for _, edu := range data.Education {
+					fmt.Fprintf(&b, "### %s\n\n", escape(edu.Degree))
+					fmt.Fprintf(&b, "%s\n\n", escape(edu.Institution))
+					if d := details(period(edu.StartDate, edu.EndDate), edu.Location); d != "" {
+						fmt.Fprintf(&b, "*%s*\n\n", escape(d))
+					}
+				}
+			}
+		case components.SectionProjects:
+			if len(data.Projects) > 0 {
+				b.WriteString("## Projects\n\n")
+				for _, project := range data.Projects {
+					if project.Link != "" {
+						fmt.Fprintf(&b, "### %s\n\n", link(project.Name, project.Link))
+					} else {
+						fmt.Fprintf(&b, "### %s\n\n", escape(project.Name))
+					}
+					if project.Description != "" {
+						fmt.Fprintf(&b, "%s\n\n", escape(strings.TrimSpace(project.Description)))
+					}
+					if len(project.Technologies) > 0 {
+						fmt.Fprintf(&b, "**Technologies:** %s\n\n", links(project.Technologies))
+					}
+				}
+			}
+		case components.SectionContributions:
+			if len(data.Contributions) > 0 {
+				b.WriteString("## Open Source\n\n")
+				for _, contribution := range data.Contributions {
+					fmt.Fprintf(&b, "### %s\n\n", title(contribution.Repo, contribution.URL))
+					if d := details(period(contribution.StartDate, contribution.EndDate), components.ContributionSummary(contribution)); d != "" {
+						fmt.Fprintf(&b, "*%s*\n\n", escape(d))
+					}
+					if contribution.Description != "" {
+						fmt.Fprintf(&b, "%s\n\n", escape(strings.TrimSpace(contribution.Description)))
+					}
+				}
+			}
+		case components.SectionPublications:
+			if len(data.Publications) > 0 {
+				b.WriteString("## Publications\n\n")
+				for _, publication := range data.Publications {
+					fmt.Fprintf(&b, "### %s\n\n", title(publication.Title, publication.URL))
+					if d := details(publication.Publisher, publication.Date); d != "" {
+						fmt.Fprintf(&b, "*%s*\n\n", escape(d))
+					}
+					if publication.Summary != "" {
+						fmt.Fprintf(&b, "%s\n\n", escape(strings.TrimSpace(publication.Summary)))
+					}
+				}
+			}
+		case components.SectionTalks:
+			if len(data.Talks) > 0 {
+				b.WriteString("## Talks\n\n")
+				for _, talk := range data.Talks {
+					fmt.Fprintf(&b, "### %s\n\n", title(talk.Title, talk.URL))
+					if d := details(talk.Event, talk.Location, talk.Date); d != "" {
+						fmt.Fprintf(&b, "*%s*\n\n", escape(d))
+					}
+					if talk.Summary != "" {
+						fmt.Fprintf(&b, "%s\n\n", escape(strings.TrimSpace(talk.Summary)))
+					}
+				}
+			}
+		case components.SectionCertifications:
+			if len(data.Certifications) > 0 {
+				b.WriteString("## Certifications\n\n")
+				for _, cert := range data.Certifications {
+					if cert.URL != "" {
+						fmt.Fprintf(&b, "### %s\n\n", link(cert.Name, cert.URL))
+					} else {
+						fmt.Fprintf(&b, "### %s\n\n", escape(cert.Name))
+					}
+					if cert.Issuer != "" {
+						fmt.Fprintf(&b, "%s\n\n", escape(cert.Issuer))
+					}
+					if d := details(components.CertificationDates(cert), credential(cert.CredentialID)); d != "" {
+						fmt.Fprintf(&b, "*%s*\n\n", escape(d))
+					}
+				}
+			}
+		case components.SectionLanguages:
+			if len(data.Languages) > 0 {
+				b.WriteString("## Languages\n\n")
+				for _, lang := range data.Languages {
+					fmt.Fprintf(&b, "- %s — %s\n", escape(lang.Language), escape(lang.Proficiency))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
+
+// title is the heading text of an entry, linked when it has a URL
+func title(text, url string) string {
+	if url == "" {
+		return escape(text)
+	}
+	return link(text, url)
+}
+
+// credential labels a credential ID, empty when there is none
+func credential(id string) string {
+	if id == "" {
+		return ""
+	}
+	return "Credential ID: " + id
+}
+
+func period(start, end string) string {
+	if start == "" {
+		return end
+	}
+	return start + " – " + end
+}
+
+// details joins the non-empty parts of a position or degree subtitle
+func details(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, " · ")
+}
+
+func links(items []components.Link) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Link != "" {
+			names = append(names, link(item.Name, item.Link))
+		} else {
+			names = append(names, escape(item.Name))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func link(text, url string) string {
+	return "[" + escape(text) + "](" + strings.ReplaceAll(url, ")", "%29") + ")"
+}
+
+// escaper backslash-escapes the characters that would start emphasis, links
+// or code spans inside running text
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
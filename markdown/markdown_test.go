@@ -0,0 +1,89 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func TestRender(t *testing.T) {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{
+		Name:   "Jane Doe",
+		Title:  "Golang Developer",
+		Email:  "jane@example.com",
+		Github: "https://github.com/jane",
+	}
+	data.Skills = []components.SkillCategory{{Category: "Languages", Items: []components.Link{{Name: "Go", Link: "https://go.dev"}, {Name: "C_plus"}}}}
+	data.Experience = []components.ExperienceItem{{
+		Company:     "ACME",
+		Position:    "Developer",
+		StartDate:   "Mar 2022",
+		EndDate:     "Present",
+		Description: []string{"Built *things*"},
+	}}
+
+	got := string(Render(data))
+	for _, want := range []string{
+		"# Jane Doe\n\n**Golang Developer**\n\n",
+		"[jane@example.com](mailto:jane@example.com) · [GitHub](https://github.com/jane)\n",
+		"- **Languages:** [Go](https://go.dev), C\\_plus\n",
+		"### Developer — ACME\n\n*Mar 2022 – Present*\n\n- Built \\*things\\*\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "## Skills") > strings.Index(got, "## Experience") {
+		t.Error("skills should come before experience, as on the page")
+	}
+	if !strings.HasSuffix(got, "\n") || strings.HasSuffix(got, "\n\n") {
+		t.Error("document should end with exactly one newline")
+	}
+}
+
+func TestRenderLayout(t *testing.T) {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Developer"}
+	data.Skills = []components.SkillCategory{{Category: "Languages", Items: []components.Link{{Name: "Go"}}}}
+	data.Experience = []components.ExperienceItem{{Company: "ACME", Position: "Developer"}}
+	data.Languages = []components.LanguageItem{{Language: "English", Proficiency: "Native"}}
+	data.Layout.Sections = []string{components.SectionLanguages, components.SectionExperience}
+
+	got := string(Render(data))
+	if strings.Contains(got, "## Skills") {
+		t.Error("skills are left out of layout.sections but rendered")
+	}
+	languages, experience := strings.Index(got, "## Languages"), strings.Index(got, "## Experience")
+	if languages < 0 || experience < 0 || languages > experience {
+		t.Errorf("want languages before experience, as in layout.sections:\n%s", got)
+	}
+}
+
+func TestRenderContributionsPublicationsAndTalks(t *testing.T) {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Developer"}
+	data.Contributions = []components.ContributionItem{{
+		Repo:         "golang/go",
+		Role:         "Contributor",
+		PullRequests: 12,
+		StartDate:    "Jan 2022",
+		EndDate:      "Present",
+		URL:          "https://github.com/golang/go",
+		Description:  "Fixes in net/http",
+	}}
+	data.Publications = []components.PublicationItem{{Title: "Profiling Go", Publisher: "Habr", Date: "Jun 2023"}}
+	data.Talks = []components.TalkItem{{Title: "Go at scale", Event: "GopherCon", Location: "Berlin", Date: "Oct 2023", URL: "https://example.com/talk"}}
+
+	got := string(Render(data))
+	for _, want := range []string{
+		"## Open Source\n\n### [golang/go](https://github.com/golang/go)\n\n*Jan 2022 – Present · Contributor, 12 pull requests*\n\nFixes in net/http\n",
+		"## Publications\n\n### Profiling Go\n\n*Habr · Jun 2023*\n",
+		"## Talks\n\n### [Go at scale](https://example.com/talk)\n\n*GopherCon · Berlin · Oct 2023*\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() is missing %q:\n%s", want, got)
+		}
+	}
+}
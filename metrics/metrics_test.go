@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExposition(t *testing.T) {
+	registry := NewRegistry()
+	views := registry.NewCounter("cv_page_views_total", "Resume page views.", "variant")
+	ratio := registry.NewGauge("cv_cache_hit_ratio", "Share of cache lookups served from the cache.", "cache")
+	latency := registry.NewHistogram("cv_pdf_generation_seconds", "PDF rendering time.", []float64{0.5, 0.1, 1}, "variant")
+
+	views.Inc("default")
+	views.Inc("default")
+	views.Inc(`back"end`)
+	ratio.Set(0.75, "pdf")
+	latency.Observe(0.05, "default")
+	latency.Observe(0.1, "default")
+	latency.Observe(3, "default")
+
+	var b strings.Builder
+	if _, err := registry.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP cv_page_views_total Resume page views.
+# TYPE cv_page_views_total counter
+cv_page_views_total{variant="back\"end"} 1
+cv_page_views_total{variant="default"} 2
+# HELP cv_cache_hit_ratio Share of cache lookups served from the cache.
+# TYPE cv_cache_hit_ratio gauge
+cv_cache_hit_ratio{cache="pdf"} 0.75
+# HELP cv_pdf_generation_seconds PDF rendering time.
+# TYPE cv_pdf_generation_seconds histogram
+cv_pdf_generation_seconds_bucket{variant="default",le="0.1"} 2
+cv_pdf_generation_seconds_bucket{variant="default",le="0.5"} 2
+cv_pdf_generation_seconds_bucket{variant="default",le="1"} 2
+cv_pdf_generation_seconds_bucket{variant="default",le="+Inf"} 3
+cv_pdf_generation_seconds_sum{variant="default"} 3.15
+cv_pdf_generation_seconds_count{variant="default"} 3
+`
+	if got := b.String(); got != want {
+		t.Errorf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("up_total", "Scrapes.").Inc()
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if !strings.Contains(rec.Body.String(), "\nup_total 1\n") {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+func TestLabelCountMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with the wrong number of labels should panic")
+		}
+	}()
+	NewRegistry().NewCounter("c_total", "c", "a", "b").Inc("only-one")
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/metrics"
+)
+
+// DefaultVariantLabel labels metrics of the full resume, rendered without -variant
+const DefaultVariantLabel = "default"
+
+// serverMetrics are the metrics exposed at /metrics
+type serverMetrics struct {
+	registry      *metrics.Registry
+	pageViews     *metrics.Counter
+	exports       *metrics.Counter
+	pdfDuration   *metrics.Histogram
+	cacheRequests *metrics.Counter
+	cacheHitRatio *metrics.Gauge
+	configValid   *metrics.Gauge
+}
+
+func newServerMetrics() *serverMetrics {
+	registry := metrics.NewRegistry()
+	return &serverMetrics{
+		registry:      registry,
+		pageViews:     registry.NewCounter("cv_page_views_total", "Resume page views.", "variant"),
+		exports:       registry.NewCounter("cv_exports_total", "Downloaded exports by format and variant.", "format", "variant"),
+		pdfDuration:   registry.NewHistogram("cv_pdf_generation_seconds", "Time spent generating PDFs; the count is the number of generated PDFs.", metrics.DefaultBuckets, "variant"),
+		cacheRequests: registry.NewCounter("cv_cache_requests_total", "Lookups of rendered documents by cache and result (hit or miss).", "cache", "result"),
+		cacheHitRatio: registry.NewGauge("cv_cache_hit_ratio", "Share of lookups served from the cache.", "cache"),
+		configValid:   registry.NewGauge("cv_config_valid", "1 when the loaded config passes validation, 0 otherwise."),
+	}
+}
+
+// variantLabel is the variant label value of the resume
+func variantLabel(resumeData *components.ResumeData) string {
+	if resumeData.Page.Variant == "" {
+		return DefaultVariantLabel
+	}
+	return resumeData.Page.Variant
+}
+
+// cacheLookup counts a cache lookup and refreshes the hit ratio of the cache
+func (m *serverMetrics) cacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheRequests.Inc(cache, result)
+
+	hits := m.cacheRequests.Value(cache, "hit")
+	misses := m.cacheRequests.Value(cache, "miss")
+	m.cacheHitRatio.Set(hits/(hits+misses), cache)
+}
+
+// countExports counts successful downloads of an export
+func (m *serverMetrics) countExports(format, variant string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status < http.StatusBadRequest {
+			m.exports.Inc(format, variant)
+		}
+	})
+}
+
+// renderCache keeps a rendered document. The resume does not change while
+// the server runs, so only the first request renders it.
+type renderCache struct {
+	name    string
+	metrics *serverMetrics
+
+	mu   sync.Mutex
+	body []byte
+}
+
+func newRenderCache(name string, m *serverMetrics) *renderCache {
+	return &renderCache{name: name, metrics: m}
+}
+
+// get returns the cached document, calling render on the first lookup and
+// again after a failed render
+func (c *renderCache) get(render func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metrics.cacheLookup(c.name, c.body != nil)
+	if c.body != nil {
+		return c.body, nil
+	}
+
+	body, err := render()
+	if err != nil {
+		return nil, err
+	}
+	c.body = body
+	return body, nil
+}
+
+// timePDF renders a PDF and records how long it took
+func (m *serverMetrics) timePDF(variant string, render func() ([]byte, error)) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		start := time.Now()
+		body, err := render()
+		if err == nil {
+			m.pdfDuration.Observe(time.Since(start).Seconds(), variant)
+		}
+		return body, err
+	}
+}
+
+// readiness reports whether the server should receive traffic: not while
+// the loaded config is invalid
+type readiness struct {
+	metrics *serverMetrics
+
+	mu  sync.RWMutex
+	err error
+}
+
+// setConfigError records the result of validating the loaded config
+func (r *readiness) setConfigError(err error) {
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+
+	valid := 1.0
+	if err != nil {
+		valid = 0
+	}
+	r.metrics.configValid.Set(valid)
+}
+
+func (r *readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	err := r.err
+	r.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// The probe is public, the problems are in the server log
+		_, _ = w.Write([]byte("config is invalid\n"))
+		return
+	}
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// serveHealth answers liveness probes: the process is up and serving
+func serveHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte("ok\n"))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/ogimage"
+)
+
+// ogImageTimeout bounds the rendering of the preview card, the download of
+// a remote photo included
+const ogImageTimeout = 30 * time.Second
+
+// photoError reports a photo that could not be loaded into the preview card
+type photoError struct {
+	err error
+}
+
+func (e *photoError) Error() string {
+	return "preview image is rendered without photo: " + e.err.Error()
+}
+
+func (e *photoError) Unwrap() error {
+	return e.err
+}
+
+// renderOGImage renders the Open Graph preview card. A photo that cannot be
+// loaded is left out instead of failing the whole card: the card comes back
+// without it, along with a *photoError.
+func renderOGImage(ctx context.Context, resumeData *components.ResumeData, static fs.FS) ([]byte, error) {
+	fonts, err := fs.Sub(static, "fonts")
+	if err != nil {
+		return nil, err
+	}
+	renderer, err := ogimage.NewRenderer(fonts)
+	if err != nil {
+		return nil, err
+	}
+
+	if resumeData.Personal.Photo == "" {
+		return renderer.Render(*resumeData, nil)
+	}
+
+	img, err := ogimage.LoadPhoto(ctx, resumeData.Personal.Photo, static)
+	if err != nil {
+		card, renderErr := renderer.Render(*resumeData, nil)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		return card, &photoError{err: err}
+	}
+	return renderer.Render(*resumeData, img)
+}
+
+// serveOGImage serves the Open Graph preview card, rendering it on the first
+// request. A card without its photo is served but not cached, so the next
+// request tries the photo again.
+func serveOGImage(resumeData *components.ResumeData, static fs.FS, cache *renderCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var withoutPhoto []byte
+		body, err := cache.get(func() ([]byte, error) {
+			// The cached card outlives the request that renders it, so it
+			// does not depend on that client staying connected
+			ctx, cancel := context.WithTimeout(context.Background(), ogImageTimeout)
+			defer cancel()
+			card, err := renderOGImage(ctx, resumeData, static)
+			if errors.As(err, new(*photoError)) {
+				withoutPhoto = card
+			}
+			return card, err
+		})
+		if withoutPhoto != nil {
+			log.Print(err)
+			body, err = withoutPhoto, nil
+		}
+		if err != nil {
+			http.Error(w, "Failed to render preview image: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+		_, err = w.Write(body)
+		if err != nil {
+			http.Error(w, "Failed to write preview image to response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package ogimage rasterizes the Open Graph preview card shown when the CV
+// link is shared in messengers and social networks.
+package ogimage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // profile photos are usually JPEG
+	"image/png"
+	"io/fs"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// Card size recommended by Open Graph consumers
+	Width  = 1200
+	Height = 630
+
+	// Font files inside the fonts file system
+	BoldFontFile    = "Montserrat-Bold.ttf"
+	RegularFontFile = "Montserrat-Regular.ttf"
+
+	// Layout in pixels
+	padding       = 80
+	photoSize     = 280
+	photoBorder   = 8
+	textGap       = 64
+	nameSize      = 64
+	minNameSize   = 36
+	titleSize     = 36
+	skillSize     = 24
+	skillPadX     = 18
+	skillPadY     = 10
+	skillGap      = 14
+	skillRadius   = 12
+	maxSkills     = 8
+	maxSkillLines = 3
+
+	// Stroke widths of the faux bold, see drawText
+	nameWeight  = 3
+	titleWeight = 1
+	skillWeight = 1
+
+	// photoTimeout bounds the download of a remote profile photo
+	photoTimeout = 10 * time.Second
+)
+
+// Colors matching the page header
+var (
+	BackgroundColor = color.RGBA{R: 0, G: 51, B: 102, A: 255}
+	TextColor       = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	SubtleTextColor = color.RGBA{R: 191, G: 219, B: 254, A: 255}
+	BadgeColor      = color.RGBA{R: 30, G: 84, B: 140, A: 255}
+)
+
+// Renderer draws preview cards with the Montserrat fonts
+type Renderer struct {
+	bold    *opentype.Font
+	regular *opentype.Font
+}
+
+// NewRenderer loads the fonts from fonts (usually the fonts directory of the
+// static assets)
+func NewRenderer(fonts fs.FS) (*Renderer, error) {
+	bold, err := loadFont(fonts, BoldFontFile)
+	if err != nil {
+		return nil, err
+	}
+	regular, err := loadFont(fonts, RegularFontFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{bold: bold, regular: regular}, nil
+}
+
+func loadFont(fonts fs.FS, path string) (*opentype.Font, error) {
+	data, err := fs.ReadFile(fonts, path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading font: %w", err)
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing font %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// LoadPhoto loads the profile photo from an http(s) URL or from the static
+// assets. Site paths such as /static/img/profile.jpg are read from static.
+func LoadPhoto(ctx context.Context, source string, static fs.FS) (image.Image, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		ctx, cancel := context.WithTimeout(ctx, photoTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating photo request: %w", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading photo: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error downloading photo: %s", resp.Status)
+		}
+		img, _, err := image.Decode(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding photo: %w", err)
+		}
+		return img, nil
+	}
+
+	name, ok := strings.CutPrefix(source, "/static/")
+	if !ok {
+		return nil, fmt.Errorf("photo %q is neither an http(s) URL nor under /static/", source)
+	}
+	file, err := static.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening photo: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding photo: %w", err)
+	}
+	return img, nil
+}
+
+// Render draws the preview card as PNG. photo may be nil, in which case
+// the text takes the full width of the card.
+func (r *Renderer) Render(data components.ResumeData, photo image.Image) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(BackgroundColor), image.Point{}, draw.Src)
+
+	textX := padding
+	if photo != nil {
+		top := (Height - photoSize) / 2
+		drawCircle(img, image.Rect(padding-photoBorder, top-photoBorder, padding+photoSize+photoBorder, top+photoSize+photoBorder), TextColor)
+		drawCircularPhoto(img, photo, image.Rect(padding, top, padding+photoSize, top+photoSize))
+		textX = padding + photoSize + textGap
+	}
+	textWidth := Width - padding - textX
+
+	// Name, shrunk until it fits on one line
+	var nameFace font.Face
+	for size := float64(nameSize); ; size -= 4 {
+		face, err := r.face(r.bold, size)
+		if err != nil {
+			return nil, err
+		}
+		if font.MeasureString(face, data.Personal.Name).Ceil()+nameWeight <= textWidth || size <= minNameSize {
+			nameFace = face
+			break
+		}
+		face.Close()
+	}
+	defer nameFace.Close()
+
+	titleFace, err := r.face(r.regular, titleSize)
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+
+	skillFace, err := r.face(r.regular, skillSize)
+	if err != nil {
+		return nil, err
+	}
+	defer skillFace.Close()
+
+	skillLines := layoutSkills(skillFace, topSkills(data.Skills, maxSkills), textWidth)
+
+	// Vertically center the text block
+	nameHeight := nameFace.Metrics().Height.Ceil()
+	titleHeight := titleFace.Metrics().Height.Ceil()
+	badgeHeight := skillFace.Metrics().Height.Ceil() + 2*skillPadY
+	blockHeight := nameHeight + titleHeight
+	if len(skillLines) > 0 {
+		blockHeight += 32 + len(skillLines)*(badgeHeight+skillGap) - skillGap
+	}
+	y := (Height - blockHeight) / 2
+
+	drawText(img, nameFace, TextColor, textX, y, data.Personal.Name, nameWeight)
+	y += nameHeight
+	drawText(img, titleFace, SubtleTextColor, textX, y, data.Personal.Title, titleWeight)
+	y += titleHeight + 32
+
+	for _, line := range skillLines {
+		x := textX
+		for _, skill := range line {
+			width := badgeWidth(skillFace, skill)
+			fillRoundedRect(img, image.Rect(x, y, x+width, y+badgeHeight), skillRadius, BadgeColor)
+			drawText(img, skillFace, TextColor, x+skillPadX, y+skillPadY, skill, skillWeight)
+			x += width + skillGap
+		}
+		y += badgeHeight + skillGap
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("error encoding preview image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *Renderer) face(f *opentype.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("error creating font face: %w", err)
+	}
+	return face, nil
+}
+
+// topSkills picks up to n skills, taking one from each category in turn so
+// the card is not filled by a single category
+func topSkills(categories []components.SkillCategory, n int) []string {
+	var skills []string
+	for i := 0; len(skills) < n; i++ {
+		added := false
+		for _, category := range categories {
+			if i < len(category.Items) && len(skills) < n {
+				skills = append(skills, category.Items[i].Name)
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return skills
+}
+
+// layoutSkills wraps skill badges into at most maxSkillLines lines of width
+func layoutSkills(face font.Face, skills []string, width int) [][]string {
+	var lines [][]string
+	var line []string
+	lineWidth := 0
+
+	for _, skill := range skills {
+		badge := badgeWidth(face, skill)
+		if len(line) > 0 && lineWidth+skillGap+badge > width {
+			lines = append(lines, line)
+			if len(lines) == maxSkillLines {
+				return lines
+			}
+			line, lineWidth = nil, 0
+		}
+		if len(line) > 0 {
+			lineWidth += skillGap
+		}
+		line = append(line, skill)
+		lineWidth += badge
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// badgeWidth is the width of a skill badge including its padding
+func badgeWidth(face font.Face, skill string) int {
+	return font.MeasureString(face, skill).Ceil() + skillWeight + 2*skillPadX
+}
+
+// drawText draws s with its top edge at y. The bundled Montserrat files are a
+// variable font, and x/image only renders its default (thin) instance, so the
+// glyphs are emboldened by over-striking them weight pixels to the right and down.
+func drawText(dst draw.Image, face font.Face, c color.Color, x, y int, s string, weight int) {
+	baseline := y + face.Metrics().Ascent.Ceil()
+	for dx := 0; dx <= weight; dx++ {
+		for dy := 0; dy <= weight; dy++ {
+			d := &font.Drawer{
+				Dst:  dst,
+				Src:  image.NewUniform(c),
+				Face: face,
+				Dot:  fixed.P(x+dx, baseline+dy),
+			}
+			d.DrawString(s)
+		}
+	}
+}
+
+// drawCircularPhoto center-crops the photo to a square, scales it into rect
+// and clips it to a circle
+func drawCircularPhoto(dst draw.Image, photo image.Image, rect image.Rectangle) {
+	b := photo.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	scaled := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), photo, crop, xdraw.Src, nil)
+
+	draw.DrawMask(dst, rect, scaled, image.Point{}, &circleMask{size: rect.Dx()}, image.Point{}, draw.Over)
+}
+
+// drawCircle fills the circle inscribed into rect
+func drawCircle(dst draw.Image, rect image.Rectangle, c color.Color) {
+	draw.DrawMask(dst, rect, image.NewUniform(c), image.Point{}, &circleMask{size: rect.Dx()}, image.Point{}, draw.Over)
+}
+
+// fillRoundedRect fills rect with rounded corners of the given radius
+func fillRoundedRect(dst draw.Image, rect image.Rectangle, radius int, c color.Color) {
+	draw.DrawMask(dst, rect, image.NewUniform(c), image.Point{}, &roundedRectMask{size: rect.Size(), radius: radius}, image.Point{}, draw.Over)
+}
+
+// circleMask is an anti-aliased alpha mask of a circle with the given diameter
+type circleMask struct {
+	size int
+}
+
+func (m *circleMask) ColorModel() color.Model { return color.AlphaModel }
+
+func (m *circleMask) Bounds() image.Rectangle { return image.Rect(0, 0, m.size, m.size) }
+
+func (m *circleMask) At(x, y int) color.Color {
+	r := float64(m.size) / 2
+	return coverage(float64(x)+0.5-r, float64(y)+0.5-r, r)
+}
+
+// roundedRectMask is an anti-aliased alpha mask of a rounded rectangle
+type roundedRectMask struct {
+	size   image.Point
+	radius int
+}
+
+func (m *roundedRectMask) ColorModel() color.Model { return color.AlphaModel }
+
+func (m *roundedRectMask) Bounds() image.Rectangle { return image.Rectangle{Max: m.size} }
+
+func (m *roundedRectMask) At(x, y int) color.Color {
+	r := float64(m.radius)
+	px, py := float64(x)+0.5, float64(y)+0.5
+
+	// Distance to the nearest corner center, only relevant inside corner squares
+	cx := min(max(px, r), float64(m.size.X)-r)
+	cy := min(max(py, r), float64(m.size.Y)-r)
+	return coverage(px-cx, py-cy, r)
+}
+
+// coverage returns the alpha of a point at (dx, dy) from the center of a
+// circle of radius r, with a one pixel soft edge
+func coverage(dx, dy, r float64) color.Alpha {
+	d2 := dx*dx + dy*dy
+	switch {
+	case d2 <= (r-1)*(r-1):
+		return color.Alpha{A: 255}
+	case d2 >= r*r:
+		return color.Alpha{}
+	}
+
+	// Linear falloff across the last pixel
+	edge := r - math.Sqrt(d2)
+	return color.Alpha{A: uint8(edge * 255)}
+}
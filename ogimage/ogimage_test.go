@@ -0,0 +1,74 @@
+package ogimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func fixture() components.ResumeData {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Golang Developer"}
+	data.Skills = []components.SkillCategory{
+		{Category: "Languages", Items: []components.Link{{Name: "Go"}, {Name: "SQL"}, {Name: "C"}}},
+		{Category: "Databases", Items: []components.Link{{Name: "Postgres"}}},
+	}
+	return data
+}
+
+func TestRender(t *testing.T) {
+	renderer, err := NewRenderer(os.DirFS("../static/fonts"))
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	red := color.RGBA{R: 255, A: 255}
+	photo := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	draw.Draw(photo, photo.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+
+	out, err := renderer.Render(fixture(), photo)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(Width, Height) {
+		t.Errorf("image size = %v, want %dx%d", size, Width, Height)
+	}
+
+	// The corner keeps the background, the photo center is covered by the photo
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != BackgroundColor {
+		t.Errorf("corner color = %v, want %v", got, BackgroundColor)
+	}
+	if got := color.RGBAModel.Convert(img.At(padding+photoSize/2, Height/2)); got != red {
+		t.Errorf("photo center color = %v, want %v", got, red)
+	}
+}
+
+func TestRenderWithoutPhoto(t *testing.T) {
+	renderer, err := NewRenderer(os.DirFS("../static/fonts"))
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	if _, err := renderer.Render(fixture(), nil); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+}
+
+func TestTopSkills(t *testing.T) {
+	got := topSkills(fixture().Skills, 3)
+	if want := []string{"Go", "Postgres", "SQL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("topSkills() = %v, want %v", got, want)
+	}
+}
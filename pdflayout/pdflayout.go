@@ -0,0 +1,169 @@
+// Package pdflayout records where each string of the resume PDF lands and
+// compares the records, so that a spacing change that moves content or pushes
+// it onto another page shows up in a test instead of in a sent resume.
+package pdflayout
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/pdftext"
+)
+
+// DefaultTolerance is how far in PDF points an element may shift before it
+// counts as moved; it absorbs the rounding of the snapshot
+const DefaultTolerance = 0.5
+
+// Element is a string drawn on a page and the point where it starts, in PDF
+// points from the bottom left corner
+type Element struct {
+	Page int
+	X, Y float64
+	Text string
+}
+
+func (e Element) String() string {
+	return fmt.Sprintf("%q at page %d (%.2f, %.2f)", e.Text, e.Page, e.X, e.Y)
+}
+
+// Snapshot is the layout of a document: its page count and the elements of
+// all pages in the order of drawing
+type Snapshot struct {
+	Pages    int
+	Elements []Element
+}
+
+// Take records the layout of a PDF. Blank strings are left out, as they do
+// not show on the page.
+func Take(pdf []byte) (Snapshot, error) {
+	pages, err := pdftext.Layout(pdf)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot := Snapshot{Pages: len(pages)}
+	for i, runs := range pages {
+		for _, run := range runs {
+			if strings.TrimSpace(run.Text) == "" {
+				continue
+			}
+			snapshot.Elements = append(snapshot.Elements, Element{Page: i + 1, X: round(run.X), Y: round(run.Y), Text: run.Text})
+		}
+	}
+	return snapshot, nil
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// Marshal writes the snapshot as text, an element per line, so that stored
+// snapshots diff well
+func (s Snapshot) Marshal() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "pages: %d\n", s.Pages)
+	for _, e := range s.Elements {
+		fmt.Fprintf(&b, "%d %.2f %.2f %q\n", e.Page, e.X, e.Y, e.Text)
+	}
+	return b.Bytes()
+}
+
+// Parse reads a snapshot written by Marshal
+func Parse(data []byte) (Snapshot, error) {
+	var s Snapshot
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lines.Buffer(nil, 1<<20)
+	if !lines.Scan() {
+		return s, fmt.Errorf("empty snapshot")
+	}
+	if _, err := fmt.Sscanf(lines.Text(), "pages: %d", &s.Pages); err != nil {
+		return s, fmt.Errorf("line 1: want the page count, got %q", lines.Text())
+	}
+	for n := 2; lines.Scan(); n++ {
+		fields := strings.SplitN(lines.Text(), " ", 4)
+		if len(fields) != 4 {
+			return s, fmt.Errorf("line %d: want page, x, y and text", n)
+		}
+		var e Element
+		var err error
+		if e.Page, err = strconv.Atoi(fields[0]); err != nil {
+			return s, fmt.Errorf("line %d: invalid page %q", n, fields[0])
+		}
+		if e.X, err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return s, fmt.Errorf("line %d: invalid x %q", n, fields[1])
+		}
+		if e.Y, err = strconv.ParseFloat(fields[2], 64); err != nil {
+			return s, fmt.Errorf("line %d: invalid y %q", n, fields[2])
+		}
+		if e.Text, err = strconv.Unquote(fields[3]); err != nil {
+			return s, fmt.Errorf("line %d: invalid text %s", n, fields[3])
+		}
+		s.Elements = append(s.Elements, e)
+	}
+	return s, lines.Err()
+}
+
+// Change is a difference between two snapshots
+type Change struct {
+	// Kind is "pages", "moved", "added" or "removed"
+	Kind string
+	// Old and New are the element before and after; only New is set for an
+	// added element and only Old for a removed one
+	Old, New Element
+	// OldPages and NewPages are the page counts of a "pages" change
+	OldPages, NewPages int
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case "pages":
+		return fmt.Sprintf("page count changed: %d -> %d", c.OldPages, c.NewPages)
+	case "moved":
+		return fmt.Sprintf("moved %q: page %d (%.2f, %.2f) -> page %d (%.2f, %.2f)",
+			c.New.Text, c.Old.Page, c.Old.X, c.Old.Y, c.New.Page, c.New.X, c.New.Y)
+	case "added":
+		return "added " + c.New.String()
+	default:
+		return "removed " + c.Old.String()
+	}
+}
+
+// Diff compares a stored snapshot with a new one. Elements are matched by
+// their text and, for repeated strings, by the order they appear in. The
+// page count change comes first, then the moved and added elements in the
+// order of the new snapshot, then the removed ones.
+func Diff(old, new Snapshot, tolerance float64) []Change {
+	var changes []Change
+	if old.Pages != new.Pages {
+		changes = append(changes, Change{Kind: "pages", OldPages: old.Pages, NewPages: new.Pages})
+	}
+
+	// Queue the old elements of each text to match them in order
+	remaining := map[string][]Element{}
+	for _, e := range old.Elements {
+		remaining[e.Text] = append(remaining[e.Text], e)
+	}
+	for _, e := range new.Elements {
+		queue := remaining[e.Text]
+		if len(queue) == 0 {
+			changes = append(changes, Change{Kind: "added", New: e})
+			continue
+		}
+		prev := queue[0]
+		remaining[e.Text] = queue[1:]
+		if prev.Page != e.Page || math.Abs(prev.X-e.X) > tolerance || math.Abs(prev.Y-e.Y) > tolerance {
+			changes = append(changes, Change{Kind: "moved", Old: prev, New: e})
+		}
+	}
+	for _, e := range old.Elements {
+		queue := remaining[e.Text]
+		if len(queue) > 0 && queue[0] == e {
+			changes = append(changes, Change{Kind: "removed", Old: e})
+			remaining[e.Text] = queue[1:]
+		}
+	}
+	return changes
+}
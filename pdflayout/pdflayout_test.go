@@ -0,0 +1,69 @@
+package pdflayout
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMarshalParse(t *testing.T) {
+	s := Snapshot{Pages: 2, Elements: []Element{
+		{1, 56.69, 787.54, "Jane Doe"},
+		{2, 10, 20.5, "• Go, \"SQL\"\n  and C"},
+	}}
+	data := s.Marshal()
+	want := "pages: 2\n1 56.69 787.54 \"Jane Doe\"\n2 10.00 20.50 \"• Go, \\\"SQL\\\"\\n  and C\"\n"
+	if string(data) != want {
+		t.Errorf("Marshal() = %q, want %q", data, want)
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, s) {
+		t.Errorf("Parse() = %+v, want %+v", parsed, s)
+	}
+
+	for _, bad := range []string{"", "1 2 3 \"x\"\n", "pages: 1\n1 2 \"x\"\n", "pages: 1\n1 x 3 \"x\"\n", "pages: 1\n1 2 3 x\n"} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%q) error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := Snapshot{Pages: 1, Elements: []Element{
+		{1, 50, 700, "Experience"},
+		{1, 50, 680, "•"},
+		{1, 50, 600, "•"},
+		{1, 50, 100, "Education"},
+		{1, 50, 80, "Languages"},
+	}}
+	new := Snapshot{Pages: 2, Elements: []Element{
+		{1, 50, 700.3, "Experience"},
+		{1, 50, 680, "•"},
+		{1, 50, 590, "•"},
+		{2, 50, 780, "Education"},
+		{2, 50, 760, "Projects"},
+	}}
+
+	var got []string
+	for _, change := range Diff(old, new, DefaultTolerance) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"page count changed: 1 -> 2",
+		`moved "•": page 1 (50.00, 600.00) -> page 1 (50.00, 590.00)`,
+		`moved "Education": page 1 (50.00, 100.00) -> page 2 (50.00, 780.00)`,
+		`added "Projects" at page 2 (50.00, 760.00)`,
+		`removed "Languages" at page 1 (50.00, 80.00)`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diff() =\n%q\nwant\n%q", got, want)
+	}
+
+	if changes := Diff(old, old, 0); len(changes) != 0 {
+		t.Errorf("Diff() of the same snapshot = %v, want none", changes)
+	}
+}
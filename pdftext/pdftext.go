@@ -0,0 +1,407 @@
+// Package pdftext reads the text and its positions back from the PDF of the
+// resume, so that tests can compare the rendered document instead of its
+// bytes, which change with the creation date on every run.
+//
+// It understands the subset of PDF written by the PDF generator: objects
+// with direct stream lengths, optionally deflated content streams and text
+// drawn in the standard fonts with the Windows-1252 encoding.
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	objectHeader = regexp.MustCompile(`(?m)^(\d+) 0 obj\b`)
+	streamStart  = regexp.MustCompile(`>>\s*stream\r?\n`)
+	length       = regexp.MustCompile(`/Length (\d+)\b`)
+	pageType     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pagesType    = regexp.MustCompile(`/Type\s*/Pages\b`)
+	kids         = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	contents     = regexp.MustCompile(`/Contents\s+(\d+) 0 R`)
+	reference    = regexp.MustCompile(`(\d+) 0 R`)
+)
+
+// object is an indirect object of the document
+type object struct {
+	dict   []byte
+	stream []byte
+}
+
+// Run is a string drawn on a page and the point where it starts, in PDF
+// points from the bottom left corner of the page
+type Run struct {
+	Text string
+	X, Y float64
+}
+
+// Pages returns the text of each page, a line for each string drawn, in the
+// order of drawing
+func Pages(pdf []byte) ([][]string, error) {
+	layout, err := Layout(pdf)
+	if err != nil {
+		return nil, err
+	}
+	pages := make([][]string, len(layout))
+	for i, runs := range layout {
+		for _, run := range runs {
+			pages[i] = append(pages[i], run.Text)
+		}
+	}
+	return pages, nil
+}
+
+// Layout returns the strings drawn on each page with their positions, in
+// the order of drawing
+func Layout(pdf []byte) ([][]Run, error) {
+	objects, err := parse(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages [][]Run
+	for _, num := range pageOrder(objects) {
+		page := objects[num]
+		match := contents.FindSubmatch(page.dict)
+		if match == nil {
+			pages = append(pages, nil)
+			continue
+		}
+		content, ok := objects[atoi(match[1])]
+		if !ok {
+			return nil, fmt.Errorf("page %d: missing content object %s", len(pages)+1, match[1])
+		}
+		stream, err := decode(content)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", len(pages)+1, err)
+		}
+		pages = append(pages, runs(stream))
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found")
+	}
+	return pages, nil
+}
+
+// parse reads the indirect objects of the document by number
+func parse(pdf []byte) (map[int]object, error) {
+	objects := map[int]object{}
+	for pos := 0; pos < len(pdf); {
+		header := objectHeader.FindSubmatchIndex(pdf[pos:])
+		if header == nil {
+			break
+		}
+		num := atoi(pdf[pos+header[2] : pos+header[3]])
+		start := pos + header[1]
+		end := bytes.Index(pdf[start:], []byte("endobj"))
+		if end < 0 {
+			return nil, fmt.Errorf("object %d is not closed", num)
+		}
+		end += start
+
+		var obj object
+		if stream := streamStart.FindIndex(pdf[start:end]); stream != nil {
+			obj.dict = pdf[start : start+stream[0]+2]
+			match := length.FindSubmatch(obj.dict)
+			if match == nil {
+				return nil, fmt.Errorf("object %d: stream without a direct length", num)
+			}
+			from, n := start+stream[1], atoi(match[1])
+			if from+n > len(pdf) {
+				return nil, fmt.Errorf("object %d: stream runs past the end of the file", num)
+			}
+			obj.stream = pdf[from : from+n]
+			// The stream may contain "endobj", look for it after the data
+			end = bytes.Index(pdf[from+n:], []byte("endobj"))
+			if end < 0 {
+				return nil, fmt.Errorf("object %d is not closed", num)
+			}
+			end += from + n
+		} else {
+			obj.dict = pdf[start:end]
+		}
+		objects[num] = obj
+		pos = end + len("endobj")
+	}
+	return objects, nil
+}
+
+// pageOrder returns the numbers of the page objects in the order of the page
+// tree
+func pageOrder(objects map[int]object) []int {
+	var pages []int
+	var walk func(num int)
+	walk = func(num int) {
+		obj := objects[num]
+		switch {
+		case pagesType.Match(obj.dict):
+			if match := kids.FindSubmatch(obj.dict); match != nil {
+				for _, ref := range reference.FindAllSubmatch(match[1], -1) {
+					walk(atoi(ref[1]))
+				}
+			}
+		case pageType.Match(obj.dict):
+			pages = append(pages, num)
+		}
+	}
+	for num, obj := range objects {
+		// The root of the tree is the only node without a parent
+		if pagesType.Match(obj.dict) && !bytes.Contains(obj.dict, []byte("/Parent")) {
+			walk(num)
+			break
+		}
+	}
+	return pages
+}
+
+// decode returns the data of a stream, inflated when it is deflated
+func decode(obj object) ([]byte, error) {
+	if !bytes.Contains(obj.dict, []byte("/FlateDecode")) {
+		return obj.stream, nil
+	}
+	r, err := zlib.NewReader(bytes.NewReader(obj.stream))
+	if err != nil {
+		return nil, fmt.Errorf("error inflating content: %w", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error inflating content: %w", err)
+	}
+	return data, nil
+}
+
+// runs returns the strings shown by the Tj and TJ operators of a content
+// stream. The position follows the text operators Td, TD, Tm, T* and TL;
+// transformations of the graphics state are ignored.
+func runs(content []byte) []Run {
+	var out []Run
+	var operands []any
+	var x, y, leading float64
+	for s := (scanner{data: content}); ; {
+		token, ok := s.next()
+		if !ok {
+			return out
+		}
+		op, isOperator := token.(operator)
+		if !isOperator {
+			operands = append(operands, token)
+			continue
+		}
+
+		nums := numbers(operands)
+		switch op {
+		case "BT":
+			x, y = 0, 0
+		case "Td", "TD":
+			if len(nums) == 2 {
+				x, y = x+nums[0], y+nums[1]
+				if op == "TD" {
+					leading = -nums[1]
+				}
+			}
+		case "Tm":
+			if len(nums) == 6 {
+				x, y = nums[4], nums[5]
+			}
+		case "TL":
+			if len(nums) == 1 {
+				leading = nums[0]
+			}
+		case "T*", "'", `"`:
+			y -= leading
+		}
+
+		if len(operands) > 0 {
+			switch last := operands[len(operands)-1].(type) {
+			case []byte:
+				if op == "Tj" || op == "'" || op == `"` {
+					out = append(out, Run{Text: decodeText(last), X: x, Y: y})
+				}
+			case []any:
+				if op == "TJ" {
+					var b []byte
+					for _, part := range last {
+						if str, ok := part.([]byte); ok {
+							b = append(b, str...)
+						}
+					}
+					out = append(out, Run{Text: decodeText(b), X: x, Y: y})
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// numbers returns the operands when they are all numbers
+func numbers(operands []any) []float64 {
+	nums := make([]float64, 0, len(operands))
+	for _, operand := range operands {
+		n, ok := operand.(float64)
+		if !ok {
+			return nil
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// decodeText converts a string of the standard fonts to UTF-8
+func decodeText(b []byte) string {
+	out, err := charmap.Windows1252.NewDecoder().Bytes(b)
+	if err != nil {
+		return string(b)
+	}
+	return string(out)
+}
+
+// operator is a content stream operator such as Td or Tj
+type operator string
+
+// scanner splits a content stream into operators and operands: strings as
+// []byte, arrays as []any, numbers as float64 and names as string
+type scanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *scanner) next() (any, bool) {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return nil, false
+	}
+	switch c := s.data[s.pos]; {
+	case c == '(':
+		return s.literal(), true
+	case c == '[':
+		s.pos++
+		var items []any
+		for {
+			s.skipSpace()
+			if s.pos >= len(s.data) || s.data[s.pos] == ']' {
+				s.pos++
+				return items, true
+			}
+			item, ok := s.next()
+			if !ok {
+				return items, true
+			}
+			items = append(items, item)
+		}
+	case c == '<' && s.pos+1 < len(s.data) && s.data[s.pos+1] != '<':
+		// Hex strings only appear with embedded fonts, whose glyph codes
+		// are not text; keep them as an empty string
+		end := bytes.IndexByte(s.data[s.pos:], '>')
+		if end < 0 {
+			end = len(s.data) - s.pos - 1
+		}
+		s.pos += end + 1
+		return []byte{}, true
+	case c == '/':
+		start := s.pos
+		s.pos++
+		s.word()
+		return string(s.data[start:s.pos]), true
+	}
+
+	start := s.pos
+	s.word()
+	if s.pos == start {
+		// A delimiter such as ] or >> outside of its context
+		s.pos++
+		return s.next()
+	}
+	token := string(s.data[start:s.pos])
+	if n, err := strconv.ParseFloat(token, 64); err == nil {
+		return n, true
+	}
+	return operator(token), true
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n', '\f', 0:
+			s.pos++
+		case '%':
+			for s.pos < len(s.data) && s.data[s.pos] != '\n' && s.data[s.pos] != '\r' {
+				s.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// word advances over a regular token, stopping at whitespace and delimiters
+func (s *scanner) word() {
+	for s.pos < len(s.data) && !bytes.ContainsRune([]byte(" \t\r\n\f\x00()<>[]{}/%"), rune(s.data[s.pos])) {
+		s.pos++
+	}
+}
+
+// literal reads a (string) with its escapes and balanced parentheses
+func (s *scanner) literal() []byte {
+	var out []byte
+	depth := 0
+	for s.pos++; s.pos < len(s.data); s.pos++ {
+		c := s.data[s.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				s.pos++
+				return out
+			}
+			depth--
+		case '\\':
+			s.pos++
+			if s.pos >= len(s.data) {
+				return out
+			}
+			c = s.data[s.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A line continuation
+				if c == '\r' && s.pos+1 < len(s.data) && s.data[s.pos+1] == '\n' {
+					s.pos++
+				}
+				continue
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				n := 0
+				for i := 0; i < 3 && s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '7'; i++ {
+					n = n*8 + int(s.data[s.pos]-'0')
+					s.pos++
+				}
+				s.pos--
+				c = byte(n)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func atoi(b []byte) int {
+	n, _ := strconv.Atoi(string(b))
+	return n
+}
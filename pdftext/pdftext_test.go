@@ -0,0 +1,93 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// document builds a PDF with a page for each content stream, the second and
+// later ones deflated
+func document(streams ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.3\n")
+	var kids []string
+	for i, content := range streams {
+		page, data := 3+2*i, []byte(content)
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+		fmt.Fprintf(&b, "%d 0 obj\n<</Type /Page\n/Parent 1 0 R\n/Contents %d 0 R>>\nendobj\n", page, page+1)
+		filter := ""
+		if i > 0 {
+			var z bytes.Buffer
+			w := zlib.NewWriter(&z)
+			w.Write(data)
+			w.Close()
+			data, filter = z.Bytes(), "/Filter /FlateDecode "
+		}
+		fmt.Fprintf(&b, "%d 0 obj\n<<%s/Length %d>>\nstream\n%s\nendstream\nendobj\n", page+1, filter, len(data), data)
+	}
+	// The page tree comes last and lists the pages in reverse, as the order
+	// of the tree wins over the order of the objects
+	slices.Reverse(kids)
+	fmt.Fprintf(&b, "1 0 obj\n<</Type /Pages\n/Kids [%s ]\n/Count %d>>\nendobj\n", strings.Join(kids, " "), len(kids))
+	b.WriteString("%%EOF\n")
+	return b.Bytes()
+}
+
+func TestPages(t *testing.T) {
+	pdf := document(
+		`BT /F1 10.00 Tf ET q 0 0 1 rg BT 10.00 20.00 Td (Jane \(Doe\)) Tj ET Q BT 10 30 Td (\225 Go\\C) Tj ET`,
+		`BT 10 20 Td [(Back) -20 (end)] TJ ET BT 1 2 Td <00410042> Tj ET (endobj) Tj`,
+	)
+	pages, err := Pages(pdf)
+	if err != nil {
+		t.Fatalf("Pages() error = %v", err)
+	}
+	want := [][]string{{"Backend", "", "endobj"}, {"Jane (Doe)", "• Go\\C"}}
+	if len(pages) != len(want) {
+		t.Fatalf("Pages() = %q, want %q", pages, want)
+	}
+	for i := range want {
+		if !slices.Equal(pages[i], want[i]) {
+			t.Errorf("page %d = %q, want %q", i+1, pages[i], want[i])
+		}
+	}
+}
+
+func TestPagesErrors(t *testing.T) {
+	for name, pdf := range map[string][]byte{
+		"no pages":        []byte("%PDF-1.3\n%%EOF\n"),
+		"short stream":    []byte("%PDF-1.3\n4 0 obj\n<</Length 999>>\nstream\nBT ET\nendstream\nendobj\n"),
+		"unclosed object": []byte("%PDF-1.3\n4 0 obj\n<</Type /Pages>>\n"),
+	} {
+		if _, err := Pages(pdf); err == nil {
+			t.Errorf("%s: Pages() error = nil, want an error", name)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	pdf := document(
+		`BT 10.00 20.00 Td (Name) Tj ET BT 5 5 Td 1 2 Td (Title) Tj ET`,
+		`BT 1 0 0 1 30 700 Tm 12 TL (First) Tj T* (Second) Tj ET BT 50 60 Td [(A) 10 (B)] TJ ET`,
+	)
+	pages, err := Layout(pdf)
+	if err != nil {
+		t.Fatalf("Layout() error = %v", err)
+	}
+	want := [][]Run{
+		{{"First", 30, 700}, {"Second", 30, 688}, {"AB", 50, 60}},
+		{{"Name", 10, 20}, {"Title", 6, 7}},
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("Layout() = %v, want %v", pages, want)
+	}
+	for i := range want {
+		if !slices.Equal(pages[i], want[i]) {
+			t.Errorf("page %d = %v, want %v", i+1, pages[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/access"
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+const (
+	// AccessKeyEnv is read when -access-key is not given
+	AccessKeyEnv = "CV_ACCESS_KEY"
+
+	// AccessParam is the query parameter of access links; the token is then
+	// kept in AccessCookie so that the PDF download sees it too
+	AccessParam  = "access"
+	AccessCookie = "cv_access"
+
+	// DefaultAccessExpiry is how long a new access link works
+	DefaultAccessExpiry = "168h"
+)
+
+// enablePrivate reveals full, the resume with its private fields, to
+// requests carrying a token signed by signer
+func (s *site) enablePrivate(full *components.ResumeData, signer *access.Signer) {
+	s.private = full
+	s.access = signer
+}
+
+// withAccess serves private to holders of a valid access token and public
+// to everyone else. A token from the link is remembered in a cookie until
+// it expires.
+func (s *site) withAccess(public, private http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		if token := r.URL.Query().Get(AccessParam); token != "" {
+			expires, err := s.access.Verify(token, now)
+			if err == nil {
+				http.SetCookie(w, &http.Cookie{
+					Name:     AccessCookie,
+					Value:    token,
+					Path:     "/",
+					Expires:  expires,
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+				s.servePrivate(private, w, r)
+				return
+			}
+		}
+
+		if cookie, err := r.Cookie(AccessCookie); err == nil {
+			if _, err := s.access.Verify(cookie.Value, now); err == nil {
+				s.servePrivate(private, w, r)
+				return
+			}
+		}
+		public.ServeHTTP(w, r)
+	})
+}
+
+// servePrivate keeps the private resume out of shared caches and search
+// engines, and the access token out of Referer headers
+func (s *site) servePrivate(private http.Handler, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	private.ServeHTTP(w, r)
+}
+
+// runAccess prints a link that reveals the private fields until it expires
+func runAccess(args []string) error {
+	flags := flag.NewFlagSet("access", flag.ExitOnError)
+	key := flags.String("key", os.Getenv(AccessKeyEnv), "key the server was started with (default $"+AccessKeyEnv+")")
+	expiresIn := flags.String("expires", DefaultAccessExpiry, "when the link stops working: a duration like 720h or a date like 2025-12-31")
+	baseURL := flags.String("base-url", "http://localhost:"+DefaultPort, "address of the server")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *key == "" {
+		return errors.New("no access key: set -key or " + AccessKeyEnv)
+	}
+
+	expires, err := parseExpiry(*expiresIn, time.Now())
+	if err != nil {
+		return err
+	}
+	if expires.IsZero() {
+		return errors.New("access links must expire")
+	}
+
+	query := url.Values{AccessParam: {access.NewSigner(*key).Sign(expires)}}
+	fmt.Printf("%s/?%s\n", strings.TrimSuffix(*baseURL, "/"), query.Encode())
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/access"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+)
+
+func TestPrivateFields(t *testing.T) {
+	s := testSite(nil)
+	full := *s.resumeData
+	full.Personal.Phone = "+380000000000"
+	full.Private = []string{"personal.phone"}
+	public := resume.Redact(full)
+	s.resumeData = &public
+
+	signer := access.NewSigner("secret")
+	s.enablePrivate(&full, signer)
+	routes := s.routes()
+
+	if body := get(t, routes, "/").Body.String(); strings.Contains(body, full.Personal.Phone) {
+		t.Error("the page shows the private phone to anonymous visitors")
+	}
+	if body := get(t, routes, "/?access=forged.token").Body.String(); strings.Contains(body, full.Personal.Phone) {
+		t.Error("the page shows the private phone with a forged token")
+	}
+	expired := signer.Sign(time.Now().Add(-time.Minute))
+	if body := get(t, routes, "/?access="+expired).Body.String(); strings.Contains(body, full.Personal.Phone) {
+		t.Error("the page shows the private phone with an expired token")
+	}
+
+	rec := get(t, routes, "/?access="+signer.Sign(time.Now().Add(time.Hour)))
+	if !strings.Contains(rec.Body.String(), full.Personal.Phone) {
+		t.Fatal("the page hides the private phone from an access link")
+	}
+	if rec.Header().Get("Cache-Control") != "private, no-store" {
+		t.Errorf("Cache-Control = %q, want private, no-store", rec.Header().Get("Cache-Control"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != AccessCookie || !cookies[0].HttpOnly {
+		t.Fatalf("cookies = %+v, want the HttpOnly access cookie", cookies)
+	}
+
+	// The cookie carries the access to the following requests
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+	next := httptest.NewRecorder()
+	routes.ServeHTTP(next, r)
+	if !strings.Contains(next.Body.String(), full.Personal.Phone) {
+		t.Error("the page hides the private phone from the access cookie")
+	}
+}
@@ -0,0 +1,420 @@
+// Package resume loads config.yaml and prepares it for rendering: picking the
+// translated config for a locale, narrowing it down to a variant and
+// validating the result.
+package resume
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"gopkg.in/yaml.v3"
+)
+
+// Date layouts accepted in startDate and endDate
+const (
+	DateLayout     = "Jan 2006"
+	YearDateLayout = "2006"
+
+	// PresentDate marks an ongoing position
+	PresentDate = "Present"
+)
+
+// LocalePath is the config translated to locale, e.g. config.uk.yaml next to
+// config.yaml. An empty locale or the default locale is the config itself.
+func LocalePath(configPath, locale string) string {
+	if locale == "" || locale == components.DefaultLocale {
+		return configPath
+	}
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + locale + ext
+}
+
+// Locales lists the default locale and the locales of the translations
+// found next to configPath, e.g. uk for config.uk.yaml
+func Locales(configPath string) ([]string, error) {
+	ext := filepath.Ext(configPath)
+	base := strings.TrimSuffix(configPath, ext)
+	matches, err := filepath.Glob(base + ".*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	locales := []string{components.DefaultLocale}
+	for _, match := range matches {
+		locale := strings.TrimSuffix(strings.TrimPrefix(match, base+"."), ext)
+		if locale != "" && !strings.Contains(locale, ".") && !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales, nil
+}
+
+// Load reads the config for locale and records the locale on the result. It
+// leaves out the expired certifications when layout.hideExpiredCertifications
+// is set.
+func Load(configPath, locale string) (*components.ResumeData, error) {
+	path := LocalePath(configPath, locale)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var resumeData components.ResumeData
+	err = yaml.Unmarshal(data, &resumeData)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	resumeData.Page.Locale = locale
+	if resumeData.Layout.HideExpiredCertifications {
+		resumeData = HideExpired(resumeData, time.Now())
+	}
+	return &resumeData, nil
+}
+
+// FindVariant returns the declared variant with the given name
+func FindVariant(data components.ResumeData, name string) (components.VariantSettings, bool) {
+	for _, variant := range data.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return components.VariantSettings{}, false
+}
+
+// ApplyVariant keeps only the entries tagged with the variant or not tagged at
+// all, and applies the variant's title and summary. An empty name returns the
+// data unchanged.
+func ApplyVariant(data components.ResumeData, name string) (components.ResumeData, error) {
+	if name == "" {
+		return data, nil
+	}
+
+	variant, ok := FindVariant(data, name)
+	if !ok {
+		return data, fmt.Errorf("unknown variant %q", name)
+	}
+
+	if variant.Title != "" {
+		data.Personal.Title = variant.Title
+	}
+	if variant.Summary != "" {
+		data.Personal.Summary = variant.Summary
+	}
+
+	data.Skills = filterVariant(data.Skills, name, func(s components.SkillCategory) []string { return s.Variants })
+	data.Experience = filterVariant(data.Experience, name, func(e components.ExperienceItem) []string { return e.Variants })
+	data.Education = filterVariant(data.Education, name, func(e components.EducationItem) []string { return e.Variants })
+	data.Projects = filterVariant(data.Projects, name, func(p components.ProjectItem) []string { return p.Variants })
+	data.Contributions = filterVariant(data.Contributions, name, func(c components.ContributionItem) []string { return c.Variants })
+	data.Publications = filterVariant(data.Publications, name, func(p components.PublicationItem) []string { return p.Variants })
+	data.Talks = filterVariant(data.Talks, name, func(t components.TalkItem) []string { return t.Variants })
+	data.Certifications = filterVariant(data.Certifications, name, func(c components.CertificationItem) []string { return c.Variants })
+	data.Page.Variant = name
+	return data, nil
+}
+
+func filterVariant[T any](items []T, name string, tags func(T) []string) []T {
+	var kept []T
+	for _, item := range items {
+		if t := tags(item); len(t) == 0 || slices.Contains(t, name) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// privateFields clear the fields and sections that config.yaml may list
+// under private
+var privateFields = map[string]func(*components.ResumeData){
+	"personal.email":    func(d *components.ResumeData) { d.Personal.Email = "" },
+	"personal.phone":    func(d *components.ResumeData) { d.Personal.Phone = "" },
+	"personal.location": func(d *components.ResumeData) { d.Personal.Location = "" },
+	"personal.linkedin": func(d *components.ResumeData) { d.Personal.Linkedin = "" },
+	"personal.github":   func(d *components.ResumeData) { d.Personal.Github = "" },
+	"personal.website":  func(d *components.ResumeData) { d.Personal.Website = "" },
+	"personal.photo":    func(d *components.ResumeData) { d.Personal.Photo = "" },
+	"skills":            func(d *components.ResumeData) { d.Skills = nil },
+	"experience":        func(d *components.ResumeData) { d.Experience = nil },
+	"education":         func(d *components.ResumeData) { d.Education = nil },
+	"projects":          func(d *components.ResumeData) { d.Projects = nil },
+	"contributions":     func(d *components.ResumeData) { d.Contributions = nil },
+	"publications":      func(d *components.ResumeData) { d.Publications = nil },
+	"talks":             func(d *components.ResumeData) { d.Talks = nil },
+	"certifications":    func(d *components.ResumeData) { d.Certifications = nil },
+	"languages":         func(d *components.ResumeData) { d.Languages = nil },
+}
+
+// Redact removes the fields and sections marked private, leaving what
+// anonymous visitors and the public static build may see
+func Redact(data components.ResumeData) components.ResumeData {
+	for _, name := range data.Private {
+		if clear, ok := privateFields[name]; ok {
+			clear(&data)
+		}
+	}
+	return data
+}
+
+// Validate reports every problem found in the config at once
+func Validate(data components.ResumeData) error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	p := data.Personal
+	if strings.TrimSpace(p.Name) == "" {
+		add("personal.name is required")
+	}
+	if strings.TrimSpace(p.Title) == "" {
+		add("personal.title is required")
+	}
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			add("personal.email %q is not a valid address", p.Email)
+		}
+	}
+	checkURL(add, "personal.linkedin", p.Linkedin)
+	checkURL(add, "personal.github", p.Github)
+	checkURL(add, "personal.website", p.Website)
+	if strings.Contains(p.Photo, "://") {
+		checkURL(add, "personal.photo", p.Photo)
+	}
+	checkURL(add, "site.baseURL", data.Site.BaseURL)
+
+	variants := map[string]bool{}
+	for i, variant := range data.Variants {
+		switch {
+		case variant.Name == "":
+			add("variants[%d].name is required", i)
+		case variants[variant.Name]:
+			add("variants[%d]: duplicate variant %q", i, variant.Name)
+		}
+		variants[variant.Name] = true
+	}
+	checkTags := func(field string, tags []string) {
+		for _, tag := range tags {
+			if !variants[tag] {
+				add("%s.variants: variant %q is not declared", field, tag)
+			}
+		}
+	}
+
+	for i, category := range data.Skills {
+		field := fmt.Sprintf("skills[%d]", i)
+		if category.Category == "" {
+			add("%s.category is required", field)
+		}
+		checkLinks(add, field+".items", category.Items)
+		checkTags(field, category.Variants)
+	}
+
+	for i, exp := range data.Experience {
+		field := fmt.Sprintf("experience[%d]", i)
+		if exp.Company == "" {
+			add("%s.company is required", field)
+		}
+		if exp.Position == "" {
+			add("%s.position is required", field)
+		}
+		checkPeriod(add, field, exp.StartDate, exp.EndDate)
+		checkLinks(add, field+".technologies", exp.Technologies)
+		checkTags(field, exp.Variants)
+	}
+
+	for i, edu := range data.Education {
+		field := fmt.Sprintf("education[%d]", i)
+		if edu.Institution == "" {
+			add("%s.institution is required", field)
+		}
+		checkPeriod(add, field, edu.StartDate, edu.EndDate)
+		checkTags(field, edu.Variants)
+	}
+
+	for i, project := range data.Projects {
+		field := fmt.Sprintf("projects[%d]", i)
+		if project.Name == "" {
+			add("%s.name is required", field)
+		}
+		checkURL(add, field+".link", project.Link)
+		checkLinks(add, field+".technologies", project.Technologies)
+		checkTags(field, project.Variants)
+	}
+
+	for i, contribution := range data.Contributions {
+		field := fmt.Sprintf("contributions[%d]", i)
+		if contribution.Repo == "" {
+			add("%s.repo is required", field)
+		}
+		if contribution.PullRequests < 0 {
+			add("%s.pullRequests must not be negative, got %d", field, contribution.PullRequests)
+		}
+		checkPeriod(add, field, contribution.StartDate, contribution.EndDate)
+		checkURL(add, field+".url", contribution.URL)
+		checkTags(field, contribution.Variants)
+	}
+
+	for i, publication := range data.Publications {
+		field := fmt.Sprintf("publications[%d]", i)
+		if publication.Title == "" {
+			add("%s.title is required", field)
+		}
+		if _, err := ParseDate(publication.Date); err != nil {
+			add("%s.date: %v", field, err)
+		}
+		checkURL(add, field+".url", publication.URL)
+		checkTags(field, publication.Variants)
+	}
+
+	for i, talk := range data.Talks {
+		field := fmt.Sprintf("talks[%d]", i)
+		if talk.Title == "" {
+			add("%s.title is required", field)
+		}
+		if talk.Event == "" {
+			add("%s.event is required", field)
+		}
+		if _, err := ParseDate(talk.Date); err != nil {
+			add("%s.date: %v", field, err)
+		}
+		checkURL(add, field+".url", talk.URL)
+		checkTags(field, talk.Variants)
+	}
+
+	for i, cert := range data.Certifications {
+		field := fmt.Sprintf("certifications[%d]", i)
+		if cert.Name == "" {
+			add("%s.name is required", field)
+		}
+		issued, err := ParseDate(cert.Date)
+		if err != nil {
+			add("%s.date: %v", field, err)
+		}
+		expires, expiresErr := ParseDate(cert.Expires)
+		if expiresErr != nil {
+			add("%s.expires: %v", field, expiresErr)
+		}
+		if err == nil && expiresErr == nil && !issued.IsZero() && !expires.IsZero() && expires.Before(issued) {
+			add("%s: expires %q is before date %q", field, cert.Expires, cert.Date)
+		}
+		checkURL(add, field+".url", cert.URL)
+		checkTags(field, cert.Variants)
+	}
+
+	for i, lang := range data.Languages {
+		if lang.Language == "" {
+			add("languages[%d].language is required", i)
+		}
+	}
+
+	for i, name := range data.Private {
+		if _, ok := privateFields[name]; !ok {
+			add("private[%d]: %q cannot be private, want one of %s", i, name, strings.Join(slices.Sorted(maps.Keys(privateFields)), ", "))
+		}
+	}
+
+	sections := map[string]bool{}
+	for i, name := range data.Layout.Sections {
+		switch {
+		case !components.IsSection(name):
+			add("layout.sections[%d]: unknown section %q, want one of %s", i, name, strings.Join(components.DefaultSections, ", "))
+		case sections[name]:
+			add("layout.sections[%d]: duplicate section %q", i, name)
+		}
+		sections[name] = true
+	}
+
+	switch data.QRCode.Content {
+	case "", "vcard", "url":
+	default:
+		add("qrCode.content must be \"vcard\" or \"url\", got %q", data.QRCode.Content)
+	}
+	if data.QRCode.Content == "url" && components.SiteURL(data) == "" {
+		add("qrCode.content is \"url\" but site.baseURL and personal.website are empty")
+	}
+
+	return errors.Join(errs...)
+}
+
+// HideExpired leaves out the certifications that expired before now. A
+// certification stays valid until the end of the month or year it expires in;
+// one with an invalid expiry date is kept for Validate to report.
+func HideExpired(data components.ResumeData, now time.Time) components.ResumeData {
+	var kept []components.CertificationItem
+	for _, cert := range data.Certifications {
+		if !Expired(cert.Expires, now) {
+			kept = append(kept, cert)
+		}
+	}
+	data.Certifications = kept
+	return data
+}
+
+// Expired reports whether an expiry date in the startDate format has passed
+func Expired(expires string, now time.Time) bool {
+	t, err := ParseDate(expires)
+	if err != nil || t.IsZero() {
+		return false
+	}
+	end := t.AddDate(0, 1, 0)
+	if _, err := time.Parse(YearDateLayout, expires); err == nil {
+		end = t.AddDate(1, 0, 0)
+	}
+	return !now.Before(end)
+}
+
+// ParseDate parses a startDate or endDate; Present and empty dates are zero
+func ParseDate(value string) (time.Time, error) {
+	if value == "" || strings.EqualFold(value, PresentDate) {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{DateLayout, YearDateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q is not in the %q or %q format", value, DateLayout, YearDateLayout)
+}
+
+func checkPeriod(add func(string, ...any), field, start, end string) {
+	startTime, err := ParseDate(start)
+	if err != nil {
+		add("%s.startDate: %v", field, err)
+	}
+	endTime, endErr := ParseDate(end)
+	if endErr != nil {
+		add("%s.endDate: %v", field, endErr)
+	}
+	if err == nil && endErr == nil && !startTime.IsZero() && !endTime.IsZero() && endTime.Before(startTime) {
+		add("%s: endDate %q is before startDate %q", field, end, start)
+	}
+}
+
+func checkLinks(add func(string, ...any), field string, links []components.Link) {
+	for i, link := range links {
+		if link.Name == "" {
+			add("%s[%d].name is required", field, i)
+		}
+		checkURL(add, fmt.Sprintf("%s[%d].link", field, i), link.Link)
+	}
+}
+
+func checkURL(add func(string, ...any), field, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add("%s %q is not an absolute http(s) URL", field, value)
+	}
+}
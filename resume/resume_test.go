@@ -0,0 +1,184 @@
+package resume
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func fixture() components.ResumeData {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Developer", Email: "jane@example.com"}
+	data.Variants = []components.VariantSettings{{Name: "backend", Title: "Backend Developer"}, {Name: "gamedev"}}
+	data.Skills = []components.SkillCategory{
+		{Category: "Languages", Items: []components.Link{{Name: "Go"}}},
+		{Category: "Engines", Items: []components.Link{{Name: "Unity"}}, Variants: []string{"gamedev"}},
+	}
+	data.Experience = []components.ExperienceItem{
+		{Company: "ACME", Position: "Go Developer", StartDate: "Mar 2022", EndDate: "Present", Variants: []string{"backend"}},
+		{Company: "Studio", Position: "Game Designer", StartDate: "2018", EndDate: "Feb 2022", Variants: []string{"gamedev"}},
+	}
+	return data
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(fixture()); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	data := fixture()
+	data.Personal.Name = ""
+	data.Personal.Github = "github.com/jane"
+	data.Experience[0].StartDate = "March 2022"
+	data.Experience[1].StartDate = "Mar 2023"
+	data.Skills[1].Variants = []string{"frontend"}
+	data.Private = []string{"personal.phone", "personal.name"}
+	data.Site.BaseURL = "cv.example.com"
+	data.Layout.Sections = []string{"projects", "summary", "projects"}
+	data.Certifications = []components.CertificationItem{
+		{Issuer: "ACME", Date: "2023", URL: "example.com/cert"},
+		{Name: "CKA", Date: "Mar 2024", Expires: "Jan 2024", Variants: []string{"frontend"}},
+	}
+	data.Contributions = []components.ContributionItem{{Repo: "golang/go", PullRequests: -1, StartDate: "2023", EndDate: "2021"}}
+	data.Publications = []components.PublicationItem{{Publisher: "Habr", Date: "Spring 2023"}}
+	data.Talks = []components.TalkItem{{Title: "Go in production", URL: "youtube.com/watch"}}
+
+	err := Validate(data)
+	if err == nil {
+		t.Fatal("Validate() error = nil, want errors")
+	}
+	for _, want := range []string{
+		"personal.name is required",
+		`personal.github "github.com/jane" is not an absolute http(s) URL`,
+		`experience[0].startDate: date "March 2022"`,
+		`experience[1]: endDate "Feb 2022" is before startDate "Mar 2023"`,
+		`skills[1].variants: variant "frontend" is not declared`,
+		`private[1]: "personal.name" cannot be private`,
+		`site.baseURL "cv.example.com" is not an absolute http(s) URL`,
+		`layout.sections[1]: unknown section "summary"`,
+		`layout.sections[2]: duplicate section "projects"`,
+		"certifications[0].name is required",
+		`certifications[0].url "example.com/cert" is not an absolute http(s) URL`,
+		`certifications[1]: expires "Jan 2024" is before date "Mar 2024"`,
+		`certifications[1].variants: variant "frontend" is not declared`,
+		"contributions[0].pullRequests must not be negative",
+		`contributions[0]: endDate "2021" is before startDate "2023"`,
+		"publications[0].title is required",
+		`publications[0].date: date "Spring 2023"`,
+		"talks[0].event is required",
+		`talks[0].url "youtube.com/watch" is not an absolute http(s) URL`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateQRCodeURL(t *testing.T) {
+	data := fixture()
+	data.QRCode.Content = "url"
+	if err := Validate(data); err == nil || !strings.Contains(err.Error(), "site.baseURL and personal.website are empty") {
+		t.Errorf("Validate() error = %v, want the missing address", err)
+	}
+
+	// Either address is enough to encode
+	data.Site.BaseURL = "https://cv.example.com"
+	if err := Validate(data); err != nil {
+		t.Errorf("Validate() with site.baseURL error = %v", err)
+	}
+	data.Site.BaseURL, data.Personal.Website = "", "https://jane.example.com"
+	if err := Validate(data); err != nil {
+		t.Errorf("Validate() with personal.website error = %v", err)
+	}
+}
+
+func TestHideExpired(t *testing.T) {
+	var data components.ResumeData
+	data.Certifications = []components.CertificationItem{
+		{Name: "Month", Expires: "Mar 2024"},
+		{Name: "Year", Expires: "2024"},
+		{Name: "Forever"},
+		{Name: "Old", Expires: "Feb 2024"},
+		{Name: "Invalid", Expires: "soon"},
+	}
+
+	var names []string
+	for _, cert := range HideExpired(data, time.Date(2024, time.March, 31, 23, 0, 0, 0, time.UTC)).Certifications {
+		names = append(names, cert.Name)
+	}
+	if want := []string{"Month", "Year", "Forever", "Invalid"}; !slices.Equal(names, want) {
+		t.Errorf("HideExpired() kept %q, want %q", names, want)
+	}
+	if !Expired("2024", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("a certification expiring in 2024 is still valid in 2025")
+	}
+}
+
+func TestApplyVariant(t *testing.T) {
+	data, err := ApplyVariant(fixture(), "backend")
+	if err != nil {
+		t.Fatalf("ApplyVariant() error = %v", err)
+	}
+	if data.Personal.Title != "Backend Developer" {
+		t.Errorf("title = %q, want the variant title", data.Personal.Title)
+	}
+	if len(data.Skills) != 1 || data.Skills[0].Category != "Languages" {
+		t.Errorf("skills = %+v, want only the untagged category", data.Skills)
+	}
+	if len(data.Experience) != 1 || data.Experience[0].Company != "ACME" {
+		t.Errorf("experience = %+v, want only the backend position", data.Experience)
+	}
+
+	if _, err := ApplyVariant(fixture(), "frontend"); err == nil {
+		t.Error("ApplyVariant() with an unknown variant should fail")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	data := fixture()
+	data.Personal.Phone = "+380000000000"
+	data.Private = []string{"personal.phone", "experience"}
+
+	public := Redact(data)
+	if public.Personal.Phone != "" || public.Experience != nil {
+		t.Errorf("Redact() kept the private fields: phone %q, %d positions", public.Personal.Phone, len(public.Experience))
+	}
+	if public.Personal.Email != data.Personal.Email || len(public.Skills) != len(data.Skills) {
+		t.Errorf("Redact() removed public fields")
+	}
+	if data.Personal.Phone == "" || len(data.Experience) == 0 {
+		t.Errorf("Redact() modified its argument")
+	}
+}
+
+func TestLoadLocale(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	for path, name := range map[string]string{configPath: "Jane", filepath.Join(dir, "config.uk.yaml"): "Яна"} {
+		if err := os.WriteFile(path, []byte("personal:\n  name: "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := Load(configPath, "uk")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if data.Personal.Name != "Яна" || data.Page.Locale != "uk" {
+		t.Errorf("Load() = %q in %q, want the uk translation", data.Personal.Name, data.Page.Locale)
+	}
+
+	if _, err := Load(configPath, "de"); err == nil {
+		t.Error("Load() of a missing translation should fail")
+	}
+
+	locales, err := Locales(configPath)
+	if err != nil || !slices.Equal(locales, []string{"en", "uk"}) {
+		t.Errorf("Locales() = %v, %v, want [en uk]", locales, err)
+	}
+}
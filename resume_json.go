@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/jsonresume"
+	"gopkg.in/yaml.v3"
+)
+
+// serveJSONResume serves the resume data in the JSON Resume schema
+func serveJSONResume(resumeData *components.ResumeData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(jsonresume.FromResumeData(*resumeData))
+		if err != nil {
+			http.Error(w, "Failed to encode resume: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// runImport converts a JSON Resume file into the config.yaml layout
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	outPath := flags.String("out", "", "write the config to this file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: import [-out config.yaml] resume.json")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one JSON Resume file")
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("error reading JSON Resume file: %w", err)
+	}
+
+	config, err := importConfig(data)
+	if err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		_, err = os.Stdout.Write(config)
+		return err
+	}
+	return os.WriteFile(*outPath, config, 0644)
+}
+
+// importConfig converts a JSON Resume document into config.yaml. Fields the
+// document leaves empty are left out, as in a hand-written config.
+func importConfig(data []byte) ([]byte, error) {
+	var resume jsonresume.Resume
+	if err := json.Unmarshal(data, &resume); err != nil {
+		return nil, fmt.Errorf("error parsing JSON Resume file: %w", err)
+	}
+
+	// Match the two-space indentation of config.yaml
+	var config bytes.Buffer
+	encoder := yaml.NewEncoder(&config)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(resume.ToResumeData()); err != nil {
+		return nil, fmt.Errorf("error encoding config: %w", err)
+	}
+	return config.Bytes(), nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestImportConfig(t *testing.T) {
+	resume := `{
+		"basics": {"name": "Jane Doe", "label": "Go Developer"},
+		"work": [{"name": "ACME", "position": "Developer", "startDate": "2023-08"}]
+	}`
+
+	got, err := importConfig([]byte(resume))
+	if err != nil {
+		t.Fatalf("importConfig() error = %v", err)
+	}
+	want := `personal:
+  name: Jane Doe
+  title: Go Developer
+experience:
+  - company: ACME
+    position: Developer
+    startDate: Aug 2023
+    endDate: Present
+`
+	if string(got) != want {
+		t.Errorf("importConfig() =\n%s\nwant only the fields set in the document:\n%s", got, want)
+	}
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/access"
+	"github.com/fairytale5571/cv_onopchenko/analytics"
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/compression"
+	"github.com/fairytale5571/cv_onopchenko/configedit"
+	"github.com/fairytale5571/cv_onopchenko/fingerprint"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+	"github.com/fairytale5571/cv_onopchenko/share"
+)
+
+// Server timeouts. The write timeout leaves room for PDF generation.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultWriteTimeout      = 60 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultShutdownTimeout   = 15 * time.Second
+)
+
+// serverOptions are the flags of the serve command that configure the listener
+type serverOptions struct {
+	addr              string
+	tlsCert           string
+	tlsKey            string
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	shutdownTimeout   time.Duration
+	logFormat         string
+	analyticsPath     string
+	adminToken        string
+	sharesPath        string
+	accessKey         string
+	revisionsDir      string
+	apiOrigins        string
+}
+
+func (o *serverOptions) register(flags *flag.FlagSet) {
+	// PORT is kept for platforms that assign the port through the environment
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = DefaultPort
+	}
+
+	flags.StringVar(&o.addr, "addr", ":"+port, "address to listen on, host:port")
+	flags.StringVar(&o.tlsCert, "tls-cert", "", "TLS certificate file; serves HTTPS together with -tls-key")
+	flags.StringVar(&o.tlsKey, "tls-key", "", "TLS private key file")
+	flags.DurationVar(&o.readHeaderTimeout, "read-header-timeout", DefaultReadHeaderTimeout, "time allowed to read request headers")
+	flags.DurationVar(&o.readTimeout, "read-timeout", DefaultReadTimeout, "time allowed to read the whole request")
+	flags.DurationVar(&o.writeTimeout, "write-timeout", DefaultWriteTimeout, "time allowed to write the response")
+	flags.DurationVar(&o.idleTimeout, "idle-timeout", DefaultIdleTimeout, "how long keep-alive connections stay open")
+	flags.DurationVar(&o.shutdownTimeout, "shutdown-timeout", DefaultShutdownTimeout, "time given to in-flight requests on SIGINT or SIGTERM")
+	flags.StringVar(&o.logFormat, "log-format", "text", "log format: text or json")
+	flags.StringVar(&o.analyticsPath, "analytics", "analytics.jsonl", "file recording visits and downloads; empty disables analytics")
+	flags.StringVar(&o.sharesPath, "shares", DefaultSharesPath, "file of the share links served at /s/; empty disables them")
+	flags.StringVar(&o.apiOrigins, "api-origins", "", "comma separated origins allowed to call "+APIPrefix+" from a browser, * for any")
+	flags.StringVar(&o.accessKey, "access-key", os.Getenv(AccessKeyEnv), "key that signs access links to the private fields; they stay hidden without one (default $"+AccessKeyEnv+")")
+	flags.StringVar(&o.revisionsDir, "revisions", DefaultRevisionsDir, "directory keeping the versions of the config replaced in the /admin editor; empty disables the editor")
+	flags.StringVar(&o.adminToken, "admin-token", os.Getenv(AdminTokenEnv), "token of the /admin pages; they are disabled without one (default $"+AdminTokenEnv+")")
+}
+
+// logger returns the structured logger for the chosen format
+func (o *serverOptions) logger() (*slog.Logger, error) {
+	switch o.logFormat {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, nil)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, nil)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q, want text or json", o.logFormat)
+}
+
+// runServe serves the resume and its exports over HTTP
+func runServe(args []string) error {
+	var (
+		opts        options
+		serverOpts  serverOptions
+		contactOpts contactOptions
+	)
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	opts.register(flags)
+	serverOpts.register(flags)
+	contactOpts.register(flags)
+	staticDir := flags.String("static", "", "directory whose files override the embedded static assets")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if (serverOpts.tlsCert == "") != (serverOpts.tlsKey == "") {
+		return errors.New("-tls-cert and -tls-key must be set together")
+	}
+
+	logger, err := serverOpts.logger()
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	static, err := staticFS(*staticDir)
+	if err != nil {
+		return err
+	}
+	assets, err := fingerprint.New(static, "/static/")
+	if err != nil {
+		return err
+	}
+
+	// What outlives a reload of the config is created once
+	metrics := newServerMetrics()
+	ready := &readiness{metrics: metrics}
+	var (
+		store   *analytics.Store
+		tracker *analytics.Tracker
+		shares  *share.Store
+		form    *contactForm
+	)
+	if serverOpts.analyticsPath != "" {
+		store, err = analytics.Open(serverOpts.analyticsPath)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		tracker = analytics.NewTracker(store)
+	}
+	if serverOpts.sharesPath != "" {
+		shares = share.NewStore(serverOpts.sharesPath)
+	}
+
+	// build loads the config and prepares the routes of the site
+	var ed *editor
+	build := func() (http.Handler, error) {
+		resumeData, err := opts.load()
+		if err != nil {
+			return nil, err
+		}
+		configErr := resume.Validate(*resumeData)
+		if configErr != nil {
+			log.Printf("Config has problems, /readyz fails until they are fixed: %v", configErr)
+		}
+		// Every reload, such as a save in the editor, updates readiness
+		ready.setConfigError(configErr)
+		// Anonymous visitors get the resume without its private fields
+		public := resume.Redact(*resumeData)
+		site := newSite(&public, static, metrics, ready)
+		site.enableFingerprints(assets)
+		if serverOpts.accessKey != "" && len(resumeData.Private) > 0 {
+			site.enablePrivate(resumeData, access.NewSigner(serverOpts.accessKey))
+		}
+		site.adminToken = serverOpts.adminToken
+
+		// The form sends to the real address even when it is private. It
+		// is created once, so that its tokens survive reloads.
+		if form == nil {
+			form, err = contactOpts.form(resumeData.Personal.Email)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if form != nil {
+			site.enableContact(form)
+		}
+		if store != nil {
+			site.enableAnalytics(store, tracker)
+		}
+		if shares != nil {
+			// Share links pick their own variant out of the full resume
+			base, err := opts.loadVariant("")
+			if err != nil {
+				return nil, err
+			}
+			publicBase := resume.Redact(*base)
+			site.enableShares(shares, &publicBase)
+		}
+		// The API serves every translation, so that one server answers
+		// for all of them
+		api, err := loadAPI(opts, apiOrigins(serverOpts.apiOrigins))
+		if err != nil {
+			return nil, err
+		}
+		site.enableAPI(api)
+		if ed != nil {
+			site.enableEditor(ed)
+		}
+		return site.routes(), nil
+	}
+
+	var handler *liveHandler
+	if serverOpts.adminToken != "" && serverOpts.revisionsDir != "" {
+		// Changes saved in the editor are served at once
+		ed = newEditor(opts, configedit.NewHistory(serverOpts.revisionsDir), func() error {
+			routes, err := build()
+			if err != nil {
+				return err
+			}
+			handler.set(routes)
+			return nil
+		})
+	}
+	routes, err := build()
+	if err != nil {
+		return err
+	}
+	handler = newLiveHandler(routes)
+
+	server := &http.Server{
+		Addr:              serverOpts.addr,
+		Handler:           logRequests(logger, compression.Handler(handler)),
+		ReadHeaderTimeout: serverOpts.readHeaderTimeout,
+		ReadTimeout:       serverOpts.readTimeout,
+		WriteTimeout:      serverOpts.writeTimeout,
+		IdleTimeout:       serverOpts.idleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the server
+	serveErr := make(chan error, 1)
+	go func() {
+		if serverOpts.tlsCert != "" {
+			logger.Info("starting server", "addr", server.Addr, "tls", true)
+			serveErr <- server.ListenAndServeTLS(serverOpts.tlsCert, serverOpts.tlsKey)
+			return
+		}
+		logger.Info("starting server", "addr", server.Addr, "tls", false)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Stop accepting connections and let in-flight requests finish
+	logger.Info("shutting down", "timeout", serverOpts.shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverOpts.shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("error shutting down server: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Info("server stopped")
+	return nil
+}
+
+// site holds what the HTTP handlers share
+type site struct {
+	resumeData *components.ResumeData
+	static     fs.FS
+	metrics    *serverMetrics
+	ready      *readiness
+
+	// Analytics are off until enableAnalytics, the admin pages without a token
+	stats      *analytics.Store
+	tracker    *analytics.Tracker
+	adminToken string
+
+	// Share links are off until enableShares
+	shares     *share.Store
+	base       *components.ResumeData
+	sharedPDFs *sharedPDFs
+
+	// The private fields stay hidden until enablePrivate
+	private *components.ResumeData
+	access  *access.Signer
+
+	// The contact form is hidden until enableContact
+	contact *contactForm
+
+	// The editor is off until enableEditor
+	editor *editor
+
+	// The read API is off until enableAPI
+	api *resumeAPI
+
+	// Static files keep their names until enableFingerprints
+	assets *fingerprint.Assets
+}
+
+// newSite prepares the handlers. The metrics and the readiness of the config
+// are shared by the sites of every reload.
+func newSite(resumeData *components.ResumeData, static fs.FS, m *serverMetrics, ready *readiness) *site {
+	return &site{
+		resumeData: resumeData,
+		static:     static,
+		metrics:    m,
+		ready:      ready,
+	}
+}
+
+// enableAnalytics records visits and downloads into store through tracker
+func (s *site) enableAnalytics(store *analytics.Store, tracker *analytics.Tracker) {
+	s.stats = store
+	s.tracker = tracker
+}
+
+// enableFingerprints serves the static files under fingerprinted names too,
+// which the pages link to and browsers cache for good
+func (s *site) enableFingerprints(assets *fingerprint.Assets) {
+	s.assets = assets
+}
+
+// routes registers the site routes on a dedicated mux
+func (s *site) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	variant := variantLabel(s.resumeData)
+
+	// Serve static files
+	if s.assets != nil {
+		mux.Handle("/static/", http.StripPrefix("/static/", s.assets))
+	} else {
+		mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(s.static))))
+	}
+
+	// Define routes
+	page := s.servePage(s.resumeData)
+	if s.private != nil {
+		page = s.withAccess(page, s.servePage(s.private))
+	}
+	mux.Handle("/", page)
+	// Only the page itself counts as a visit, not every unknown path
+	mux.Handle("/{$}", s.trackVisits(analytics.KindView, page))
+
+	// Export PDF endpoint using the new Maroto library
+	pdfCache := newRenderCache("pdf", s.metrics)
+	var pdf http.Handler = genMarotoPdf(s.resumeData, pdfCache, s.metrics)
+	if s.private != nil {
+		pdf = s.withAccess(pdf, genMarotoPdf(s.private, newRenderCache("private_pdf", s.metrics), s.metrics))
+	}
+	mux.Handle("/export-pdf", s.trackVisits(analytics.KindDownload, s.metrics.countExports("pdf", variant, pdf)))
+
+	// Resume data in the JSON Resume schema
+	mux.Handle("/resume.json", s.metrics.countExports("json", variant, serveJSONResume(s.resumeData)))
+
+	// Europass XML for upload to the Europass portal
+	mux.Handle("/europass.xml", s.metrics.countExports("europass", variant, serveEuropass(s.resumeData)))
+
+	// Contact card and its QR code
+	mux.Handle("/contact.vcf", s.metrics.countExports("vcard", variant, serveVCard(s.resumeData)))
+	mux.Handle("/qr.png", serveQRCode(s.resumeData))
+
+	// Open Graph preview image for shared links
+	mux.Handle(components.OGImagePath, serveOGImage(s.resumeData, s.static, newRenderCache("og_image", s.metrics)))
+
+	// Resume data for other tools
+	if s.api != nil {
+		s.api.routes(mux)
+	}
+
+	// Probes and metrics for the cluster
+	mux.HandleFunc("/healthz", serveHealth)
+	mux.Handle("/readyz", s.ready)
+	mux.Handle("/metrics", s.metrics.registry.Handler())
+
+	// Messages from the contact form
+	if s.contact != nil {
+		mux.Handle("POST /contact", s.contact)
+	}
+
+	// Login of the admin pages opened in a browser
+	if s.adminToken != "" {
+		mux.HandleFunc("/admin/login", serveLogin(s.adminToken))
+		mux.HandleFunc("POST /admin/logout", serveLogout)
+	}
+
+	// Editing the config from the browser
+	if s.editor != nil && s.adminToken != "" {
+		s.editor.routes(mux, s.adminToken)
+	}
+
+	// Visit statistics for the owner
+	if s.stats != nil && s.adminToken != "" {
+		mux.Handle("/admin/stats", requireLogin(s.adminToken, serveStats(s.resumeData, s.stats)))
+		mux.Handle("/admin/stats.json", requireToken(s.adminToken, serveStatsJSON(s.stats)))
+	}
+
+	// Links sent to individual recipients, managed from the admin API
+	if s.shares != nil {
+		mux.HandleFunc("/s/{token}", s.serveSharedPage)
+		mux.HandleFunc("/s/{token}/export-pdf", s.serveSharedPDF)
+		if s.adminToken != "" {
+			mux.Handle("GET /admin/shares", requireToken(s.adminToken, http.HandlerFunc(s.serveShareList)))
+			mux.Handle("POST /admin/shares", requireToken(s.adminToken, http.HandlerFunc(s.serveShareCreate)))
+			mux.Handle("DELETE /admin/shares/{token}", requireToken(s.adminToken, http.HandlerFunc(s.serveShareRevoke)))
+		}
+	}
+
+	return mux
+}
+
+// servePage renders the resume page of data
+func (s *site) servePage(data *components.ResumeData) http.Handler {
+	variant := variantLabel(data)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := *data
+		if s.contact != nil {
+			page.Page.ContactToken = s.contact.token()
+		}
+		var buf bytes.Buffer
+		err := components.Resume(page).Render(r.Context(), &buf)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		html := buf.Bytes()
+		if s.assets != nil {
+			html = s.assets.Rewrite(html)
+		}
+
+		// The page is checked on every visit, so that it picks up the new
+		// names of changed assets; private pages are not stored at all
+		if w.Header().Get("Cache-Control") == "" {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(html)
+		s.metrics.pageViews.Inc(variant)
+	})
+}
+
+// liveHandler serves the routes of the latest config
+type liveHandler struct {
+	current atomic.Pointer[http.Handler]
+}
+
+func newLiveHandler(routes http.Handler) *liveHandler {
+	h := &liveHandler{}
+	h.set(routes)
+	return h
+}
+
+// set replaces the routes; requests in flight finish on the old ones
+func (h *liveHandler) set(routes http.Handler) {
+	h.current.Store(&routes)
+}
+
+func (h *liveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*h.current.Load()).ServeHTTP(w, r)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/fingerprint"
+)
+
+func testSite(configErr error) *site {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Developer"}
+	data.Page.Variant = "backend"
+	m := newServerMetrics()
+	ready := &readiness{metrics: m}
+	ready.setConfigError(configErr)
+	return newSite(&data, fstest.MapFS{}, m, ready)
+}
+
+func get(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestProbes(t *testing.T) {
+	routes := testSite(nil).routes()
+	if rec := get(t, routes, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200", rec.Code)
+	}
+	if rec := get(t, routes, "/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("/readyz = %d, want 200", rec.Code)
+	}
+
+	unready := testSite(errors.New("personal.name is required")).routes()
+	rec := get(t, unready, "/readyz")
+	if rec.Code != http.StatusServiceUnavailable || strings.Contains(rec.Body.String(), "personal.name") {
+		t.Errorf("/readyz with an invalid config = %d %q, want 503 without the details", rec.Code, rec.Body.String())
+	}
+	if rec := get(t, unready, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("/healthz with an invalid config = %d, want 200", rec.Code)
+	}
+}
+
+func TestReadinessFollowsReloads(t *testing.T) {
+	s := testSite(errors.New("personal.name is required"))
+	routes := s.routes()
+	// A reload that fixes the config updates the shared readiness
+	s.ready.setConfigError(nil)
+	if rec := get(t, routes, "/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("/readyz after the config was fixed = %d, want 200", rec.Code)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	routes := testSite(nil).routes()
+	get(t, routes, "/")
+	get(t, routes, "/")
+	get(t, routes, "/resume.json")
+
+	body := get(t, routes, "/metrics").Body.String()
+	for _, want := range []string{
+		`cv_page_views_total{variant="backend"} 2`,
+		`cv_exports_total{format="json",variant="backend"} 1`,
+		"cv_config_valid 1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderCacheHitRatio(t *testing.T) {
+	m := newServerMetrics()
+	cache := newRenderCache("pdf", m)
+	renders := 0
+	render := func() ([]byte, error) {
+		renders++
+		return []byte("%PDF"), nil
+	}
+
+	for range 4 {
+		if _, err := cache.get(render); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if renders != 1 {
+		t.Errorf("rendered %d times, want once", renders)
+	}
+	if got := m.cacheHitRatio.Value("pdf"); got != 0.75 {
+		t.Errorf("hit ratio = %v, want 0.75", got)
+	}
+}
+
+func TestPageFingerprints(t *testing.T) {
+	static := fstest.MapFS{"css/style.css": {Data: []byte("body {}")}}
+	assets, err := fingerprint.New(static, "/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := testSite(nil)
+	s.enableFingerprints(assets)
+	routes := s.routes()
+
+	rec := get(t, routes, "/")
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("page Cache-Control = %q, want no-cache", got)
+	}
+	link := assets.Path("css/style.css")
+	if !strings.Contains(rec.Body.String(), `href="`+link+`"`) {
+		t.Errorf("page does not link %s", link)
+	}
+
+	if rec := get(t, routes, link); rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != fingerprint.ImmutableCacheControl {
+		t.Errorf("GET %s = %d with Cache-Control %q, want an immutable file", link, rec.Code, rec.Header().Get("Cache-Control"))
+	}
+}
+
+func TestOGImageWithoutPhotoIsNotCached(t *testing.T) {
+	var data components.ResumeData
+	data.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Developer", Photo: "/static/img/missing.png"}
+	cache := newRenderCache("og_image", newServerMetrics())
+	handler := serveOGImage(&data, os.DirFS("static"), cache)
+
+	rec := get(t, handler, components.OGImagePath)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("preview without the photo = %d %q, want a PNG", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if cache.body != nil {
+		t.Error("the preview without the photo was cached")
+	}
+
+	data.Personal.Photo = ""
+	get(t, handler, components.OGImagePath)
+	if cache.body == nil {
+		t.Error("the complete preview was not cached")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package share
+
+import "os"
+
+// lockFile is a no-op where flock is not available; the store is then only
+// safe within a single process
+func lockFile(file *os.File) error {
+	return nil
+}
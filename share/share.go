@@ -0,0 +1,364 @@
+// Package share keeps the links minted for individual recipients of the
+// resume. Each link is bound to a variant, may expire or be revoked, and
+// remembers when it was opened and whether the PDF was downloaded.
+package share
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Link statuses
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// tokenBytes is the amount of randomness in a token
+const tokenBytes = 16
+
+// Kinds of the activity of a link
+const (
+	kindView     = "view"
+	kindDownload = "download"
+)
+
+// ErrNotFound is returned for tokens that were never minted
+var ErrNotFound = errors.New("share link not found")
+
+// Link is a share link sent to one recipient
+type Link struct {
+	Token     string    `json:"token"`
+	Recipient string    `json:"recipient"`
+	Variant   string    `json:"variant,omitempty"`
+	Created   time.Time `json:"created"`
+	Expires   time.Time `json:"expires,omitzero"`
+	Revoked   time.Time `json:"revoked,omitzero"`
+
+	Views          int       `json:"views"`
+	FirstViewed    time.Time `json:"firstViewed,omitzero"`
+	LastViewed     time.Time `json:"lastViewed,omitzero"`
+	Downloads      int       `json:"downloads"`
+	LastDownloaded time.Time `json:"lastDownloaded,omitzero"`
+}
+
+// Status tells whether the link still opens the resume at now
+func (l Link) Status(now time.Time) string {
+	switch {
+	case !l.Revoked.IsZero():
+		return StatusRevoked
+	case !l.Expires.IsZero() && !now.Before(l.Expires):
+		return StatusExpired
+	}
+	return StatusActive
+}
+
+// activity is a view or download of a link, a line of the activity log
+type activity struct {
+	Token string    `json:"token"`
+	Kind  string    `json:"kind"`
+	Time  time.Time `json:"time"`
+}
+
+// Store keeps the links in a JSON file and their views and downloads in a
+// JSON Lines log next to it, so that visits append a line instead of
+// rewriting the links. The files are read on every call, so links minted or
+// revoked from the command line take effect in a running server without a
+// restart. Changes are made under a lock on a .lock file, which the command
+// line and the server share.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore uses the file at path, which is created with the first link
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// ActivityPath is the log of views and downloads kept for the links at path,
+// such as shares.activity.jsonl for shares.json
+func ActivityPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".activity.jsonl"
+}
+
+// lock holds the lock of the store for this process and for the others
+// sharing the file, until the returned function is called
+func (s *Store) lock() (func(), error) {
+	s.mu.Lock()
+	file, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("error locking share links: %w", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		s.mu.Unlock()
+		return nil, fmt.Errorf("error locking share links: %w", err)
+	}
+	return func() {
+		// Closing the file releases the lock
+		file.Close()
+		s.mu.Unlock()
+	}, nil
+}
+
+// Create mints a link for recipient. A zero expires never expires.
+func (s *Store) Create(recipient, variant string, expires, now time.Time) (Link, error) {
+	token, err := newToken()
+	if err != nil {
+		return Link{}, err
+	}
+	link := Link{
+		Token:     token,
+		Recipient: recipient,
+		Variant:   variant,
+		Created:   now.UTC(),
+		Expires:   expires.UTC(),
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return Link{}, err
+	}
+	defer unlock()
+	links, err := s.read()
+	if err != nil {
+		return Link{}, err
+	}
+	return link, s.write(append(links, link))
+}
+
+// List returns all links, newest first
+func (s *Store) List() ([]Link, error) {
+	links, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(links)
+	return links, nil
+}
+
+// Get returns the link with the token
+func (s *Store) Get(token string) (Link, error) {
+	links, err := s.load()
+	if err != nil {
+		return Link{}, err
+	}
+	i := slices.IndexFunc(links, func(l Link) bool { return l.Token == token })
+	if i < 0 {
+		return Link{}, ErrNotFound
+	}
+	return links[i], nil
+}
+
+// Revoke stops the link from opening the resume
+func (s *Store) Revoke(token string, now time.Time) (Link, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return Link{}, err
+	}
+	defer unlock()
+	links, err := s.read()
+	if err != nil {
+		return Link{}, err
+	}
+	i := slices.IndexFunc(links, func(l Link) bool { return l.Token == token })
+	if i < 0 {
+		return Link{}, ErrNotFound
+	}
+	if links[i].Revoked.IsZero() {
+		links[i].Revoked = now.UTC()
+	}
+	if err := s.write(links); err != nil {
+		return Link{}, err
+	}
+	return s.withActivity(links[i])
+}
+
+// RecordView notes that the link was opened at now
+func (s *Store) RecordView(token string, now time.Time) error {
+	return s.record(activity{Token: token, Kind: kindView, Time: now.UTC()})
+}
+
+// RecordDownload notes that the PDF was downloaded through the link at now
+func (s *Store) RecordDownload(token string, now time.Time) error {
+	return s.record(activity{Token: token, Kind: kindDownload, Time: now.UTC()})
+}
+
+// record appends a view or download of an existing link to the activity log
+func (s *Store) record(a activity) error {
+	line, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	links, err := s.read()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(links, func(l Link) bool { return l.Token == a.Token }) {
+		return ErrNotFound
+	}
+
+	file, err := os.OpenFile(ActivityPath(s.path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("error recording share link activity: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("error recording share link activity: %w", err)
+	}
+	return file.Close()
+}
+
+// load returns the links with their views and downloads
+func (s *Store) load() ([]Link, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	links, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.readActivity()
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		apply(&links[i], activities)
+	}
+	return links, nil
+}
+
+// withActivity adds the views and downloads of the log to link. Callers hold
+// the lock.
+func (s *Store) withActivity(link Link) (Link, error) {
+	activities, err := s.readActivity()
+	if err != nil {
+		return Link{}, err
+	}
+	apply(&link, activities)
+	return link, nil
+}
+
+// apply adds the views and downloads of link in activities to the counts
+// kept in the links file
+func apply(link *Link, activities []activity) {
+	for _, a := range activities {
+		if a.Token != link.Token {
+			continue
+		}
+		switch a.Kind {
+		case kindView:
+			link.Views++
+			if link.FirstViewed.IsZero() || a.Time.Before(link.FirstViewed) {
+				link.FirstViewed = a.Time
+			}
+			if a.Time.After(link.LastViewed) {
+				link.LastViewed = a.Time
+			}
+		case kindDownload:
+			link.Downloads++
+			if a.Time.After(link.LastDownloaded) {
+				link.LastDownloaded = a.Time
+			}
+		}
+	}
+}
+
+// readActivity reads the activity log; a missing log has no activity
+func (s *Store) readActivity() ([]activity, error) {
+	data, err := os.ReadFile(ActivityPath(s.path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading share link activity: %w", err)
+	}
+
+	var activities []activity
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; lines.Scan(); n++ {
+		if len(lines.Bytes()) == 0 {
+			continue
+		}
+		var a activity
+		if err := json.Unmarshal(lines.Bytes(), &a); err != nil {
+			return nil, fmt.Errorf("error parsing share link activity %s line %d: %w", ActivityPath(s.path), n, err)
+		}
+		activities = append(activities, a)
+	}
+	return activities, lines.Err()
+}
+
+// read returns the links as kept in the links file. Callers hold the lock.
+func (s *Store) read() ([]Link, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading share links: %w", err)
+	}
+
+	var links []Link
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("error parsing share links %s: %w", s.path, err)
+	}
+	return links, nil
+}
+
+// write replaces the file through a rename so that readers never see half
+// of it
+func (s *Store) write(links []Link) error {
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing share links: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing share links: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing share links: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error writing share links: %w", err)
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", fmt.Errorf("error generating share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,162 @@
+package share
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.json")
+	store := NewStore(path)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	if links, err := store.List(); err != nil || len(links) != 0 {
+		t.Fatalf("List() of a missing file = %v, %v, want no links", links, err)
+	}
+
+	acme, err := store.Create("Acme", "backend", now.Add(48*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	globex, err := store.Create("Globex", "", time.Time{}, now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(acme.Token) < 20 || acme.Token == globex.Token {
+		t.Errorf("tokens %q and %q are not long and unique", acme.Token, globex.Token)
+	}
+
+	if err := store.RecordView(acme.Token, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordView(acme.Token, now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordDownload(acme.Token, now.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second store on the same file sees the changes, as the server sees
+	// links minted from the command line
+	got, err := NewStore(path).Get(acme.Token)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Views != 2 || !got.FirstViewed.Equal(now.Add(time.Hour)) || !got.LastViewed.Equal(now.Add(2*time.Hour)) || got.Downloads != 1 {
+		t.Errorf("Get() = %+v, want 2 views and 1 download", got)
+	}
+
+	links, err := store.List()
+	if err != nil || len(links) != 2 || links[0].Recipient != "Globex" {
+		t.Errorf("List() = %+v, %v, want Globex first", links, err)
+	}
+
+	if _, err := store.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := store.RecordView("missing", now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RecordView(missing) error = %v, want ErrNotFound", err)
+	}
+
+	revoked, err := store.Revoke(globex.Token, now)
+	if err != nil || revoked.Status(now) != StatusRevoked {
+		t.Errorf("Revoke() = %+v, %v, want a revoked link", revoked, err)
+	}
+}
+
+func TestViewsDoNotRewriteLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.json")
+	store := NewStore(path)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	link, err := store.Create("Acme", "", time.Time{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RecordView(link.Token, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordDownload(link.Token, now); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(before, after) {
+		t.Errorf("recording activity rewrote %s:\n%s", path, after)
+	}
+
+	// Revoking keeps the activity
+	revoked, err := store.Revoke(link.Token, now)
+	if err != nil || revoked.Views != 1 || revoked.Downloads != 1 {
+		t.Errorf("Revoke() = %+v, %v, want the view and the download", revoked, err)
+	}
+}
+
+func TestStoresShareTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.json")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Separate stores lock like separate processes: only through the file
+	server, cli := NewStore(path), NewStore(path)
+	viewed, err := server.Create("Viewed", "", time.Time{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range n {
+			if err := server.RecordView(viewed.Token, now); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range n {
+			if _, err := cli.Create(fmt.Sprint("Recipient ", i), "", time.Time{}, now); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	links, err := NewStore(path).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != n+1 {
+		t.Fatalf("List() has %d links, want %d: a write was lost", len(links), n+1)
+	}
+	if links[n].Views != n {
+		t.Errorf("the first link has %d views, want %d", links[n].Views, n)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		link Link
+		want string
+	}{
+		{"no expiry", Link{}, StatusActive},
+		{"expires later", Link{Expires: now.Add(time.Minute)}, StatusActive},
+		{"expired", Link{Expires: now}, StatusExpired},
+		{"revoked", Link{Expires: now.Add(time.Hour), Revoked: now.Add(-time.Hour)}, StatusRevoked},
+	} {
+		if got := tc.link.Status(now); got != tc.want {
+			t.Errorf("%s: Status() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/resume"
+	"github.com/fairytale5571/cv_onopchenko/share"
+)
+
+const (
+	// DefaultSharesPath is the file of the share link store
+	DefaultSharesPath = "shares.json"
+
+	// ExpiryDateLayout is the date form of -expires, the link stops working
+	// at the start of that day (UTC)
+	ExpiryDateLayout = "2006-01-02"
+)
+
+// sharePath is the address of the resume behind a share link
+func sharePath(token string) string {
+	return "/s/" + token
+}
+
+// parseExpiry reads an expiry given as a duration from now, such as 720h,
+// or as a date. Empty means the link never expires.
+func parseExpiry(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("expiry %q is not in the future", value)
+		}
+		return now.Add(d), nil
+	}
+	date, err := time.Parse(ExpiryDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expiry %q is neither a duration like 720h nor a date like 2025-12-31", value)
+	}
+	if !date.After(now) {
+		return time.Time{}, fmt.Errorf("expiry %q is not in the future", value)
+	}
+	return date, nil
+}
+
+// shareLink is a link as reported by the admin API
+type shareLink struct {
+	share.Link
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+func newShareLink(link share.Link, now time.Time) shareLink {
+	return shareLink{Link: link, Path: sharePath(link.Token), Status: link.Status(now)}
+}
+
+// sharedPDFs caches the PDF of every variant opened through share links
+type sharedPDFs struct {
+	metrics *serverMetrics
+
+	mu     sync.Mutex
+	caches map[string]*renderCache
+}
+
+func (p *sharedPDFs) cache(variant string) *renderCache {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.caches[variant] == nil {
+		p.caches[variant] = newRenderCache("shared_pdf", p.metrics)
+	}
+	return p.caches[variant]
+}
+
+// enableShares serves the links in store. base is the full resume that each
+// link narrows to its variant.
+func (s *site) enableShares(store *share.Store, base *components.ResumeData) {
+	s.shares = store
+	s.base = base
+	s.sharedPDFs = &sharedPDFs{metrics: s.metrics, caches: map[string]*renderCache{}}
+}
+
+// sharedResume looks up the link of the request and the resume it opens. It
+// answers the request itself when the link does not work.
+func (s *site) sharedResume(w http.ResponseWriter, r *http.Request) (share.Link, *components.ResumeData, bool) {
+	link, err := s.shares.Get(r.PathValue("token"))
+	if errors.Is(err, share.ErrNotFound) {
+		http.NotFound(w, r)
+		return link, nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return link, nil, false
+	}
+
+	switch link.Status(time.Now()) {
+	case share.StatusExpired:
+		http.Error(w, "This link has expired", http.StatusGone)
+		return link, nil, false
+	case share.StatusRevoked:
+		http.Error(w, "This link is no longer available", http.StatusGone)
+		return link, nil, false
+	}
+
+	data, err := resume.ApplyVariant(*s.base, link.Variant)
+	if err != nil {
+		// The variant was removed from the config after the link was minted
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return link, nil, false
+	}
+	data.Page.PDFPath = sharePath(link.Token) + "/export-pdf"
+
+	// Keep the token out of search engines and of Referer headers sent
+	// to the sites the resume links to
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	return link, &data, true
+}
+
+// serveSharedPage serves the page of a share link and notes that it was opened
+func (s *site) serveSharedPage(w http.ResponseWriter, r *http.Request) {
+	link, data, ok := s.sharedResume(w, r)
+	if !ok {
+		return
+	}
+
+	rec := &statusRecorder{ResponseWriter: w}
+	s.servePage(data).ServeHTTP(rec, r)
+	if rec.status < http.StatusBadRequest {
+		if err := s.shares.RecordView(link.Token, time.Now()); err != nil {
+			slog.Error("error recording share link view", "recipient", link.Recipient, "error", err)
+		}
+	}
+}
+
+// serveSharedPDF serves the PDF of a share link and notes the download
+func (s *site) serveSharedPDF(w http.ResponseWriter, r *http.Request) {
+	link, data, ok := s.sharedResume(w, r)
+	if !ok {
+		return
+	}
+
+	rec := &statusRecorder{ResponseWriter: w}
+	pdf := genMarotoPdf(data, s.sharedPDFs.cache(link.Variant), s.metrics)
+	s.metrics.countExports("pdf", variantLabel(data), pdf).ServeHTTP(rec, r)
+	if rec.status < http.StatusBadRequest {
+		if err := s.shares.RecordDownload(link.Token, time.Now()); err != nil {
+			slog.Error("error recording share link download", "recipient", link.Recipient, "error", err)
+		}
+	}
+}
+
+// serveShareList lists the share links with their status and visits
+func (s *site) serveShareList(w http.ResponseWriter, r *http.Request) {
+	links, err := s.shares.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	list := make([]shareLink, 0, len(links))
+	for _, link := range links {
+		list = append(list, newShareLink(link, now))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// serveShareCreate mints a link from the recipient, variant and expires
+// form values
+func (s *site) serveShareCreate(w http.ResponseWriter, r *http.Request) {
+	recipient := strings.TrimSpace(r.FormValue("recipient"))
+	if recipient == "" {
+		http.Error(w, "recipient is required", http.StatusBadRequest)
+		return
+	}
+	variant := r.FormValue("variant")
+	if _, ok := resume.FindVariant(*s.base, variant); variant != "" && !ok {
+		http.Error(w, fmt.Sprintf("unknown variant %q", variant), http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	expires, err := parseExpiry(r.FormValue("expires"), now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	link, err := s.shares.Create(recipient, variant, expires, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newShareLink(link, now))
+}
+
+// serveShareRevoke revokes the link named in the path
+func (s *site) serveShareRevoke(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	link, err := s.shares.Revoke(r.PathValue("token"), now)
+	if errors.Is(err, share.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newShareLink(link, now))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		slog.Error("error encoding JSON response", "error", err)
+	}
+}
+
+// runShare manages the share links from the command line
+func runShare(args []string) error {
+	usage := "Usage: share create -recipient name [-variant name] [-expires 720h|2025-12-31]\n       share list\n       share revoke token"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	flags := flag.NewFlagSet("share "+args[0], flag.ExitOnError)
+	storePath := flags.String("store", DefaultSharesPath, "file of the share links")
+	baseURL := flags.String("base-url", "http://localhost:"+DefaultPort, "address of the server, used to print full links")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	now := time.Now()
+	url := func(link share.Link) string {
+		return strings.TrimSuffix(*baseURL, "/") + sharePath(link.Token)
+	}
+
+	switch args[0] {
+	case "create":
+		configPath := flags.String("config", DefaultConfigPath, "path to the resume config, used to check the variant")
+		recipient := flags.String("recipient", "", "who the link is sent to, e.g. the company")
+		variant := flags.String("variant", "", "variant the link opens, empty for the full resume")
+		expiresIn := flags.String("expires", "", "when the link stops working: a duration like 720h or a date like 2025-12-31")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *recipient == "" {
+			return errors.New("-recipient is required")
+		}
+		if *variant != "" {
+			data, err := resume.Load(*configPath, "")
+			if err != nil {
+				return err
+			}
+			if _, ok := resume.FindVariant(*data, *variant); !ok {
+				return fmt.Errorf("unknown variant %q", *variant)
+			}
+		}
+		expires, err := parseExpiry(*expiresIn, now)
+		if err != nil {
+			return err
+		}
+
+		link, err := share.NewStore(*storePath).Create(*recipient, *variant, expires, now)
+		if err != nil {
+			return err
+		}
+		fmt.Println(url(link))
+		return nil
+
+	case "list":
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		links, err := share.NewStore(*storePath).List()
+		if err != nil {
+			return err
+		}
+
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "RECIPIENT\tVARIANT\tSTATUS\tEXPIRES\tVIEWS\tLAST VIEWED\tDOWNLOADS\tLINK")
+		for _, link := range links {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%d\t%s\t%d\t%s\n",
+				link.Recipient, orDash(link.Variant), link.Status(now), formatTime(link.Expires),
+				link.Views, formatTime(link.LastViewed), link.Downloads, url(link))
+		}
+		return table.Flush()
+
+	case "revoke":
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return errors.New("expected exactly one token")
+		}
+		// Accept the full link as printed by create and list
+		token := flags.Arg(0)
+		token = token[strings.LastIndex(token, "/")+1:]
+
+		link, err := share.NewStore(*storePath).Revoke(token, now)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Revoked the link of %s\n", link.Recipient)
+		return nil
+	}
+
+	return fmt.Errorf("unknown share command %q\n%s", args[0], usage)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
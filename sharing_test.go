@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+	"github.com/fairytale5571/cv_onopchenko/share"
+)
+
+func shareSite(t *testing.T) (*site, *share.Store) {
+	t.Helper()
+	var base components.ResumeData
+	base.Personal = components.PersonalInfo{Name: "Jane Doe", Title: "Developer"}
+	base.Variants = []components.VariantSettings{{Name: "backend", Title: "Backend Developer"}}
+
+	store := share.NewStore(filepath.Join(t.TempDir(), "shares.json"))
+	s := testSite(nil)
+	s.adminToken = "secret"
+	s.enableShares(store, &base)
+	return s, store
+}
+
+func TestSharedPage(t *testing.T) {
+	s, store := shareSite(t)
+	routes := s.routes()
+	now := time.Now()
+
+	link, err := store.Create("Acme", "backend", time.Time{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := get(t, routes, sharePath(link.Token))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, "Backend Developer") {
+		t.Fatalf("%s = %d, want the backend variant", sharePath(link.Token), rec.Code)
+	}
+	if !strings.Contains(body, `data-href="`+sharePath(link.Token)+`/export-pdf"`) {
+		t.Errorf("the export button does not download the PDF of the link")
+	}
+	if rec.Header().Get("Referrer-Policy") != "no-referrer" {
+		t.Errorf("Referrer-Policy = %q, want no-referrer", rec.Header().Get("Referrer-Policy"))
+	}
+
+	got, err := store.Get(link.Token)
+	if err != nil || got.Views != 1 || got.FirstViewed.IsZero() || got.Downloads != 0 {
+		t.Errorf("after a view the link is %+v, %v", got, err)
+	}
+
+	if rec := get(t, routes, "/s/unknown"); rec.Code != http.StatusNotFound {
+		t.Errorf("/s/unknown = %d, want 404", rec.Code)
+	}
+
+	expired, err := store.Create("Globex", "", now.Add(-time.Hour), now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(t, routes, sharePath(expired.Token)); rec.Code != http.StatusGone {
+		t.Errorf("expired link = %d, want 410", rec.Code)
+	}
+	if rec := get(t, routes, sharePath(expired.Token)+"/export-pdf"); rec.Code != http.StatusGone {
+		t.Errorf("PDF of an expired link = %d, want 410", rec.Code)
+	}
+}
+
+func TestShareAdmin(t *testing.T) {
+	s, store := shareSite(t)
+	routes := s.routes()
+
+	admin := func(method, path string, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, r)
+		return rec
+	}
+
+	if rec := admin(http.MethodPost, "/admin/shares", url.Values{"recipient": {"Acme"}, "variant": {"frontend"}}); rec.Code != http.StatusBadRequest {
+		t.Errorf("creating a link of an unknown variant = %d, want 400", rec.Code)
+	}
+
+	rec := admin(http.MethodPost, "/admin/shares", url.Values{"recipient": {"Acme"}, "variant": {"backend"}, "expires": {"720h"}})
+	var created shareLink
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("POST /admin/shares = %d %q", rec.Code, rec.Body.String())
+	}
+	if created.Status != share.StatusActive || created.Path != sharePath(created.Token) || created.Expires.IsZero() {
+		t.Errorf("created link = %+v", created)
+	}
+
+	if rec := get(t, routes, "/admin/shares"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("/admin/shares without a token = %d, want 401", rec.Code)
+	}
+
+	if rec := admin(http.MethodDelete, "/admin/shares/"+created.Token, nil); rec.Code != http.StatusOK {
+		t.Errorf("DELETE /admin/shares/<token> = %d, want 200", rec.Code)
+	}
+	if rec := get(t, routes, created.Path); rec.Code != http.StatusGone {
+		t.Errorf("revoked link = %d, want 410", rec.Code)
+	}
+
+	var list []shareLink
+	rec = admin(http.MethodGet, "/admin/shares", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0].Status != share.StatusRevoked {
+		t.Errorf("GET /admin/shares = %q, want the revoked link", rec.Body.String())
+	}
+
+	if links, _ := store.List(); len(links) != 1 {
+		t.Errorf("store has %d links, want 1", len(links))
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Time{
+		"":           {},
+		"48h":        now.Add(48 * time.Hour),
+		"2024-06-01": time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		got, err := parseExpiry(value, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseExpiry(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"-1h", "2024-04-01", "next week"} {
+		if _, err := parseExpiry(value, now); err == nil {
+			t.Errorf("parseExpiry(%q) succeeded, want an error", value)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package sitemap writes the sitemap.xml and robots.txt that tell search
+// engines which pages and documents of the published site to index.
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Namespace is the XML namespace of the sitemaps protocol
+const Namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// urlSet is the root element of a sitemap
+type urlSet struct {
+	XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []url    `xml:"url"`
+}
+
+type url struct {
+	Loc string `xml:"loc"`
+}
+
+// Marshal returns the sitemap listing the absolute URLs locs in order
+func Marshal(locs []string) ([]byte, error) {
+	set := urlSet{}
+	for _, loc := range locs {
+		set.URLs = append(set.URLs, url{Loc: loc})
+	}
+	out, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// Robots returns a robots.txt that lets every crawler index the site and
+// points them to the sitemap, when its absolute URL is known
+func Robots(sitemapURL string) []byte {
+	var b strings.Builder
+	b.WriteString("User-agent: *\nAllow: /\n")
+	if sitemapURL != "" {
+		fmt.Fprintf(&b, "\nSitemap: %s\n", sitemapURL)
+	}
+	return []byte(b.String())
+}
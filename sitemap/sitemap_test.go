@@ -0,0 +1,42 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	doc, err := Marshal([]string{"https://cv.example.com/", "https://cv.example.com/uk/?a=1&b=2"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.HasPrefix(string(doc), xml.Header) {
+		t.Errorf("sitemap has no XML header:\n%s", doc)
+	}
+
+	var set struct {
+		XMLName xml.Name
+		URLs    []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(doc, &set); err != nil {
+		t.Fatalf("sitemap is not valid XML: %v", err)
+	}
+	if set.XMLName.Space != Namespace || set.XMLName.Local != "urlset" {
+		t.Errorf("root element = %v, want urlset in %s", set.XMLName, Namespace)
+	}
+	if len(set.URLs) != 2 || set.URLs[1].Loc != "https://cv.example.com/uk/?a=1&b=2" {
+		t.Errorf("urls = %+v", set.URLs)
+	}
+}
+
+func TestRobots(t *testing.T) {
+	if got := string(Robots("https://cv.example.com/sitemap.xml")); !strings.HasSuffix(got, "\nSitemap: https://cv.example.com/sitemap.xml\n") {
+		t.Errorf("Robots() = %q, want the sitemap", got)
+	}
+	if got := string(Robots("")); strings.Contains(got, "Sitemap") {
+		t.Errorf("Robots(\"\") = %q, want no sitemap", got)
+	}
+}
@@ -34,7 +34,7 @@ document.addEventListener('DOMContentLoaded', function() {
       // Create a link to download the PDF
       const a = document.createElement('a');
       a.style.display = 'none';
-      a.href = '/export-pdf';
+      a.href = exportButton.dataset.href || '/export-pdf';
       a.download = 'resume.pdf';
       
       // Append to the document and trigger the download
@@ -57,9 +57,10 @@ document.addEventListener('DOMContentLoaded', function() {
     const darkMode = localStorage.getItem('darkMode');
     
     if (darkMode === null) {
-      // Use system preference
-      const prefersDark = window.matchMedia('(prefers-color-scheme: dark)').matches;
-      localStorage.setItem('darkMode', prefersDark);
+      // Use the theme the page was built with, or the system preference
+      const theme = document.documentElement.dataset.theme;
+      const prefersDark = theme === 'dark' ||
+        (theme === 'system' && window.matchMedia('(prefers-color-scheme: dark)').matches);
       document.documentElement.classList.toggle('dark', prefersDark);
     } else {
       document.documentElement.classList.toggle('dark', darkMode === 'true');
@@ -70,57 +71,46 @@ document.addEventListener('DOMContentLoaded', function() {
   initTheme();
 
   // Toggle theme
-  const themeToggle = document.querySelector('.theme-toggle');
+  const themeToggle = document.getElementById('theme-toggle');
   if (themeToggle) {
     themeToggle.addEventListener('click', function() {
-      // Оптимизация переключения темы
-      // Сначала добавляем/удаляем класс
       const isDark = document.documentElement.classList.toggle('dark');
       localStorage.setItem('darkMode', isDark);
-      
-      // Затем оптимизируем анимацию для списков
-      const lists = document.querySelectorAll('ul.list-disc');
-      lists.forEach(list => {
-        // Временно отключаем переходы для списков
-        list.style.transition = 'none';
-        
-        // Принудительно применяем стили
-        window.getComputedStyle(list).opacity;
-        
-        // Восстанавливаем переходы через небольшую задержку
-        setTimeout(() => {
-          list.style.transition = '';
-        }, 50);
-      });
-      
-      // Специальная обработка для оптимизированных списков
-      const optimizedLists = document.querySelectorAll('.theme-optimized-list');
-      optimizedLists.forEach(list => {
-        // Полностью отключаем анимацию для списков
-        list.style.transition = 'none';
-      });
-      
-      // Оптимизируем анимацию для элементов списка
-      const listItems = document.querySelectorAll('li');
-      listItems.forEach(item => {
-        // Применяем цвет напрямую для более быстрого обновления
-        item.style.color = isDark ? 
-          'hsl(0, 0%, 98%)' : // Светлый текст для темной темы
-          'hsl(240, 10%, 3.9%)'; // Темный текст для светлой темы
-      });
-      
-      // Специальная обработка для оптимизированных элементов списка
-      const optimizedItems = document.querySelectorAll('.theme-optimized-item');
-      optimizedItems.forEach(item => {
-        // Применяем цвет с короткой анимацией
-        item.style.transition = 'color 0.15s ease';
-        item.style.color = isDark ? 
-          'hsl(0, 0%, 98%)' : // Светлый текст для темной темы
-          'hsl(240, 10%, 3.9%)'; // Темный текст для светлой темы
-      });
-      
+
       // Создаем пользовательское событие для обновления других компонентов
       document.dispatchEvent(new CustomEvent('themeChanged', { detail: { isDark } }));
     });
   }
-}); 
\ No newline at end of file
+
+  // Send the contact form without leaving the page
+  const contactForm = document.getElementById('contact-form');
+
+  if (contactForm) {
+    const status = document.getElementById('contact-status');
+    contactForm.addEventListener('submit', function(event) {
+      event.preventDefault();
+      const button = contactForm.querySelector('button[type="submit"]');
+      button.disabled = true;
+      status.textContent = 'Sending…';
+
+      fetch(contactForm.action, {
+        method: 'POST',
+        headers: { 'Accept': 'application/json' },
+        body: new URLSearchParams(new FormData(contactForm)),
+      })
+        .then(response => response.json())
+        .then(reply => {
+          status.textContent = reply.message;
+          if (reply.ok) {
+            contactForm.reset();
+          }
+        })
+        .catch(() => {
+          status.textContent = 'Sorry, the message could not be sent. Please try again later.';
+        })
+        .finally(() => {
+          button.disabled = false;
+        });
+    });
+  }
+});
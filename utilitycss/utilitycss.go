@@ -0,0 +1,585 @@
+// Package utilitycss writes the stylesheet of the Tailwind utility classes
+// that the templates use, so that the page needs no CSS framework from a
+// CDN. It knows the subset of Tailwind used by this site with its theme:
+// the colors are the CSS variables of style.css. Unknown classes are left to
+// the other stylesheets, as Tailwind does.
+package utilitycss
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// classAttribute matches the static class attributes of templ and HTML
+var classAttribute = regexp.MustCompile(`\bclass=(?:"([^"]*)"|'([^']*)')`)
+
+// Classes returns the classes used in the class attributes of src
+func Classes(src []byte) []string {
+	var classes []string
+	for _, match := range classAttribute.FindAllSubmatch(src, -1) {
+		for _, class := range strings.Fields(string(match[1]) + " " + string(match[2])) {
+			if !slices.Contains(classes, class) {
+				classes = append(classes, class)
+			}
+		}
+	}
+	return classes
+}
+
+// Breakpoints are the min-width media queries of the responsive variants
+var breakpoints = []struct {
+	name  string
+	width string
+}{
+	{"sm", "640px"},
+	{"md", "768px"},
+	{"lg", "1024px"},
+	{"xl", "1280px"},
+}
+
+// states are the pseudo-class variants
+var states = map[string]string{
+	"hover":         ":hover",
+	"focus":         ":focus",
+	"focus-visible": ":focus-visible",
+	"disabled":      ":disabled",
+}
+
+// rule is a generated utility
+type rule struct {
+	class string
+	// utility is the position in the utility table, which orders the rules
+	// the way Tailwind does, e.g. padding before padding-bottom
+	utility    int
+	breakpoint int
+	// variant rules such as hover: come after the plain ones
+	variant  bool
+	selector string
+	decls    []string
+}
+
+// Generate returns the stylesheet of the known utilities among classes,
+// preceded by the base styles that Tailwind resets the browser styles with
+func Generate(classes []string) []byte {
+	var rules []rule
+	seen := map[string]bool{}
+	for _, class := range classes {
+		if seen[class] {
+			continue
+		}
+		seen[class] = true
+		if r, ok := parse(class); ok {
+			rules = append(rules, r)
+		}
+	}
+	slices.SortFunc(rules, func(a, b rule) int {
+		return cmp.Or(
+			cmp.Compare(a.breakpoint, b.breakpoint),
+			compareBool(a.variant, b.variant),
+			cmp.Compare(a.utility, b.utility),
+			cmp.Compare(a.class, b.class),
+		)
+	})
+
+	var b bytes.Buffer
+	b.WriteString("/* Generated by cmd/utilitycss from the classes of components/*.templ. DO NOT EDIT. */\n\n")
+	b.WriteString(preflight)
+	open := 0
+	for _, r := range rules {
+		if r.breakpoint != open {
+			if open != 0 {
+				b.WriteString("}\n\n")
+			}
+			fmt.Fprintf(&b, "@media (min-width: %s) {\n", breakpoints[r.breakpoint-1].width)
+			open = r.breakpoint
+		}
+		indent := strings.Repeat("  ", min(open, 1))
+		fmt.Fprintf(&b, "%s%s {\n", indent, r.selector)
+		for _, decl := range r.decls {
+			fmt.Fprintf(&b, "%s  %s;\n", indent, decl)
+		}
+		fmt.Fprintf(&b, "%s}\n", indent)
+		if open == 0 {
+			b.WriteString("\n")
+		}
+	}
+	if open != 0 {
+		b.WriteString("}\n")
+	}
+	return bytes.TrimRight(b.Bytes(), "\n")
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// parse splits the variants off class and looks its utility up
+func parse(class string) (rule, bool) {
+	parts := strings.Split(class, ":")
+	name := parts[len(parts)-1]
+	r := rule{class: class, selector: "." + escape(class)}
+
+	var pseudo, parent string
+	for _, variant := range parts[:len(parts)-1] {
+		if state, ok := states[variant]; ok {
+			pseudo += state
+			continue
+		}
+		if variant == "dark" {
+			parent = ".dark "
+			continue
+		}
+		i := slices.IndexFunc(breakpoints, func(bp struct{ name, width string }) bool { return bp.name == variant })
+		if i < 0 || r.breakpoint != 0 {
+			return r, false
+		}
+		r.breakpoint = i + 1
+	}
+
+	for i, u := range utilities {
+		arg, ok := u.match(name)
+		if !ok {
+			continue
+		}
+		decls := u.css(arg)
+		if len(decls) == 0 {
+			continue
+		}
+		r.utility, r.decls = i, decls
+		r.variant = pseudo != "" || parent != ""
+		r.selector = parent + r.selector + pseudo + u.children
+		return r, true
+	}
+	return r, false
+}
+
+// escape makes a class usable in a selector
+func escape(class string) string {
+	var b strings.Builder
+	for i, c := range class {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-', c == '_', c >= '0' && c <= '9' && i > 0:
+			b.WriteRune(c)
+		default:
+			b.WriteString(`\` + string(c))
+		}
+	}
+	return b.String()
+}
+
+// utility is a family of classes: the exact name, or the prefix followed by
+// a value that css turns into declarations
+type utility struct {
+	name     string
+	prefix   bool
+	css      func(arg string) []string
+	children string
+}
+
+func (u utility) match(class string) (string, bool) {
+	if !u.prefix {
+		return "", class == u.name
+	}
+	return strings.CutPrefix(class, u.name+"-")
+}
+
+// is is a utility of a single class
+func is(name string, decls ...string) utility {
+	return utility{name: name, css: func(string) []string { return decls }}
+}
+
+// with is a utility whose value is looked up by values
+func with(prefix string, values func(string) string, properties ...string) utility {
+	return utility{name: prefix, prefix: true, css: func(arg string) []string {
+		value := values(arg)
+		if value == "" {
+			return nil
+		}
+		var decls []string
+		for _, property := range properties {
+			decls = append(decls, property+": "+value)
+		}
+		return decls
+	}}
+}
+
+// spacing is the Tailwind spacing scale, a quarter of a rem per step
+func spacing(arg string) string {
+	switch arg {
+	case "0":
+		return "0px"
+	case "px":
+		return "1px"
+	}
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil || n < 0 || n > 96 || n*2 != float64(int(n*2)) {
+		return ""
+	}
+	return strconv.FormatFloat(n/4, 'f', -1, 64) + "rem"
+}
+
+// orAuto is spacing that also accepts auto
+func orAuto(arg string) string {
+	if arg == "auto" {
+		return "auto"
+	}
+	return spacing(arg)
+}
+
+// size is spacing with the keywords of width and height
+func size(screen string) func(string) string {
+	return func(arg string) string {
+		switch arg {
+		case "auto":
+			return "auto"
+		case "full":
+			return "100%"
+		case "screen":
+			return screen
+		}
+		return spacing(arg)
+	}
+}
+
+// themeColors are the colors of the theme, defined as CSS variables
+var themeColors = []string{
+	"background", "foreground", "card", "card-foreground", "popover", "popover-foreground",
+	"primary", "primary-foreground", "secondary", "secondary-foreground",
+	"muted", "muted-foreground", "accent", "accent-foreground",
+	"destructive", "destructive-foreground", "border", "input", "ring",
+}
+
+// color resolves a theme color with an optional opacity such as /70
+func color(arg string) string {
+	name, opacity, hasOpacity := strings.Cut(arg, "/")
+	if hasOpacity {
+		n, err := strconv.Atoi(opacity)
+		if err != nil || n < 0 || n > 100 {
+			return ""
+		}
+		opacity = " / " + strconv.FormatFloat(float64(n)/100, 'f', -1, 64)
+	}
+
+	switch name {
+	case "white":
+		return "rgb(255 255 255" + opacity + ")"
+	case "black":
+		return "rgb(0 0 0" + opacity + ")"
+	case "transparent":
+		return "transparent"
+	case "current":
+		return "currentColor"
+	}
+	if !slices.Contains(themeColors, name) {
+		return ""
+	}
+	return "hsl(var(--" + name + ")" + opacity + ")"
+}
+
+func lookup(values map[string]string) func(string) string {
+	return func(arg string) string { return values[arg] }
+}
+
+func grid(property string) func(string) string {
+	return func(arg string) string {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > 12 {
+			return ""
+		}
+		if property == "span" {
+			return fmt.Sprintf("span %d / span %d", n, n)
+		}
+		return fmt.Sprintf("repeat(%d, minmax(0, 1fr))", n)
+	}
+}
+
+func borderWidth(arg string) string {
+	switch arg {
+	case "0", "2", "4", "8":
+		return arg + "px"
+	}
+	return ""
+}
+
+const transitionTiming = "transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1)"
+
+func transition(properties string) utility {
+	return is("transition-"+properties, "transition-property: "+transitionProperties[properties], transitionTiming, "transition-duration: 150ms")
+}
+
+var transitionProperties = map[string]string{
+	"colors":    "color, background-color, border-color, text-decoration-color, fill, stroke",
+	"shadow":    "box-shadow",
+	"transform": "transform",
+	"opacity":   "opacity",
+}
+
+var (
+	fontSizes = map[string][2]string{
+		"xs":   {"0.75rem", "1rem"},
+		"sm":   {"0.875rem", "1.25rem"},
+		"base": {"1rem", "1.5rem"},
+		"lg":   {"1.125rem", "1.75rem"},
+		"xl":   {"1.25rem", "1.75rem"},
+		"2xl":  {"1.5rem", "2rem"},
+		"3xl":  {"1.875rem", "2.25rem"},
+		"4xl":  {"2.25rem", "2.5rem"},
+	}
+	maxWidths = map[string]string{
+		"xs": "20rem", "sm": "24rem", "md": "28rem", "lg": "32rem", "xl": "36rem",
+		"2xl": "42rem", "3xl": "48rem", "4xl": "56rem", "5xl": "64rem", "6xl": "72rem", "7xl": "80rem",
+		"full": "100%", "none": "none",
+	}
+	// The theme sets the normal weight to 500, like the medium one
+	fontWeights = map[string]string{"normal": "500", "medium": "500", "semibold": "600", "bold": "700"}
+	radii       = map[string]string{
+		"none": "0px", "sm": "calc(var(--radius) - 4px)", "md": "calc(var(--radius) - 2px)",
+		"lg": "var(--radius)", "full": "9999px",
+	}
+	shadows = map[string]string{
+		"sm":   "0 1px 2px 0 rgb(0 0 0 / 0.05)",
+		"md":   "0 4px 6px -1px rgb(0 0 0 / 0.1), 0 2px 4px -2px rgb(0 0 0 / 0.1)",
+		"lg":   "0 10px 15px -3px rgb(0 0 0 / 0.1), 0 4px 6px -4px rgb(0 0 0 / 0.1)",
+		"xl":   "0 20px 25px -5px rgb(0 0 0 / 0.1), 0 8px 10px -6px rgb(0 0 0 / 0.1)",
+		"none": "0 0 #0000",
+	}
+)
+
+// utilities in the order of Tailwind's output
+var utilities = []utility{
+	is("fixed", "position: fixed"),
+	is("absolute", "position: absolute"),
+	is("relative", "position: relative"),
+	is("sticky", "position: sticky"),
+	with("top", spacing, "top"),
+	with("right", spacing, "right"),
+	with("bottom", spacing, "bottom"),
+	with("left", spacing, "left"),
+	with("col-span", grid("span"), "grid-column"),
+	with("m", orAuto, "margin"),
+	with("mx", orAuto, "margin-left", "margin-right"),
+	with("my", orAuto, "margin-top", "margin-bottom"),
+	with("mt", orAuto, "margin-top"),
+	with("mr", orAuto, "margin-right"),
+	with("mb", orAuto, "margin-bottom"),
+	with("ml", orAuto, "margin-left"),
+	is("block", "display: block"),
+	is("inline-block", "display: inline-block"),
+	is("inline", "display: inline"),
+	is("flex", "display: flex"),
+	is("inline-flex", "display: inline-flex"),
+	is("grid", "display: grid"),
+	is("hidden", "display: none"),
+	with("h", size("100vh"), "height"),
+	is("min-h-screen", "min-height: 100vh"),
+	with("w", size("100vw"), "width"),
+	with("max-w", lookup(maxWidths), "max-width"),
+	is("flex-1", "flex: 1 1 0%"),
+	is("flex-shrink-0", "flex-shrink: 0"),
+	is("shrink-0", "flex-shrink: 0"),
+	is("flex-grow", "flex-grow: 1"),
+	is("grow", "flex-grow: 1"),
+	is("list-disc", "list-style-type: disc"),
+	is("list-decimal", "list-style-type: decimal"),
+	is("list-none", "list-style-type: none"),
+	with("grid-cols", grid("repeat"), "grid-template-columns"),
+	is("flex-row", "flex-direction: row"),
+	is("flex-col", "flex-direction: column"),
+	is("flex-wrap", "flex-wrap: wrap"),
+	is("items-start", "align-items: flex-start"),
+	is("items-end", "align-items: flex-end"),
+	is("items-center", "align-items: center"),
+	is("justify-start", "justify-content: flex-start"),
+	is("justify-end", "justify-content: flex-end"),
+	is("justify-center", "justify-content: center"),
+	is("justify-between", "justify-content: space-between"),
+	with("gap", spacing, "gap"),
+	with("gap-x", spacing, "column-gap"),
+	with("gap-y", spacing, "row-gap"),
+	{name: "space-x", prefix: true, css: with("", spacing, "margin-left").css, children: " > :not([hidden]) ~ :not([hidden])"},
+	{name: "space-y", prefix: true, css: with("", spacing, "margin-top").css, children: " > :not([hidden]) ~ :not([hidden])"},
+	is("overflow-hidden", "overflow: hidden"),
+	is("overflow-auto", "overflow: auto"),
+	is("rounded", "border-radius: 0.25rem"),
+	with("rounded", lookup(radii), "border-radius"),
+	is("border", "border-width: 1px"),
+	with("border", borderWidth, "border-width"),
+	is("border-t", "border-top-width: 1px"),
+	with("border-t", borderWidth, "border-top-width"),
+	is("border-r", "border-right-width: 1px"),
+	with("border-r", borderWidth, "border-right-width"),
+	is("border-b", "border-bottom-width: 1px"),
+	with("border-b", borderWidth, "border-bottom-width"),
+	is("border-l", "border-left-width: 1px"),
+	with("border-l", borderWidth, "border-left-width"),
+	with("border", color, "border-color"),
+	with("bg", color, "background-color"),
+	is("object-contain", "object-fit: contain"),
+	is("object-cover", "object-fit: cover"),
+	with("p", spacing, "padding"),
+	with("px", spacing, "padding-left", "padding-right"),
+	with("py", spacing, "padding-top", "padding-bottom"),
+	with("pt", spacing, "padding-top"),
+	with("pr", spacing, "padding-right"),
+	with("pb", spacing, "padding-bottom"),
+	with("pl", spacing, "padding-left"),
+	is("text-left", "text-align: left"),
+	is("text-center", "text-align: center"),
+	is("text-right", "text-align: right"),
+	is("font-sans", "font-family: Montserrat, sans-serif"),
+	{name: "text", prefix: true, css: func(arg string) []string {
+		if size, ok := fontSizes[arg]; ok {
+			return []string{"font-size: " + size[0], "line-height: " + size[1]}
+		}
+		return nil
+	}},
+	with("font", lookup(fontWeights), "font-weight"),
+	with("text", color, "color"),
+	is("underline", "text-decoration-line: underline"),
+	is("no-underline", "text-decoration-line: none"),
+	with("opacity", func(arg string) string {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 || n > 100 {
+			return ""
+		}
+		return strconv.FormatFloat(float64(n)/100, 'f', -1, 64)
+	}, "opacity"),
+	is("shadow", "box-shadow: 0 1px 3px 0 rgb(0 0 0 / 0.1), 0 1px 2px -1px rgb(0 0 0 / 0.1)"),
+	with("shadow", lookup(shadows), "box-shadow"),
+	is("transition", "transition-property: color, background-color, border-color, text-decoration-color, fill, stroke, opacity, box-shadow, transform", transitionTiming, "transition-duration: 150ms"),
+	transition("colors"),
+	transition("opacity"),
+	transition("shadow"),
+	transition("transform"),
+	with("duration", func(arg string) string {
+		if _, err := strconv.Atoi(arg); err != nil {
+			return ""
+		}
+		return arg + "ms"
+	}, "transition-duration"),
+}
+
+// preflight is the part of Tailwind's reset of the browser styles that
+// the page relies on
+const preflight = `*,
+::before,
+::after {
+  box-sizing: border-box;
+  border-width: 0;
+  border-style: solid;
+  border-color: #e5e7eb;
+}
+
+html {
+  line-height: 1.5;
+  -webkit-text-size-adjust: 100%;
+  tab-size: 4;
+  font-family: Montserrat, sans-serif;
+}
+
+body {
+  margin: 0;
+  line-height: inherit;
+}
+
+h1,
+h2,
+h3,
+h4,
+h5,
+h6 {
+  font-size: inherit;
+  font-weight: inherit;
+}
+
+a {
+  color: inherit;
+  text-decoration: inherit;
+}
+
+b,
+strong {
+  font-weight: bolder;
+}
+
+button,
+input,
+textarea,
+select {
+  font-family: inherit;
+  font-size: 100%;
+  font-weight: inherit;
+  line-height: inherit;
+  color: inherit;
+  margin: 0;
+  padding: 0;
+}
+
+button {
+  background-color: transparent;
+  background-image: none;
+  cursor: pointer;
+}
+
+:disabled {
+  cursor: default;
+}
+
+blockquote,
+dl,
+dd,
+h1,
+h2,
+h3,
+h4,
+h5,
+h6,
+hr,
+figure,
+p,
+pre {
+  margin: 0;
+}
+
+ol,
+ul,
+menu {
+  list-style: none;
+  margin: 0;
+  padding: 0;
+}
+
+textarea {
+  resize: vertical;
+}
+
+img,
+svg,
+video,
+canvas {
+  display: block;
+  vertical-align: middle;
+}
+
+img,
+video {
+  max-width: 100%;
+  height: auto;
+}
+
+[hidden] {
+  display: none;
+}
+
+`
@@ -0,0 +1,42 @@
+package utilitycss
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestClasses(t *testing.T) {
+	src := []byte(`<div class="flex  gap-4"><a class='hover:underline' href="#">x</a><span class={ "dynamic" }></span></div>`)
+	got := Classes(src)
+	want := []string{"flex", "gap-4", "hover:underline"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Classes() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	css := string(Generate([]string{"p-4", "hover:underline", "md:grid-cols-2", "bg-secondary/70", "dark:text-primary", "p-4", "not-a-utility"}))
+
+	for _, want := range []string{
+		".p-4 {\n  padding: 1rem;\n}",
+		".hover\\:underline:hover {\n  text-decoration-line: underline;\n}",
+		"@media (min-width: 768px) {\n  .md\\:grid-cols-2 {\n    grid-template-columns: repeat(2, minmax(0, 1fr));\n  }\n}",
+		".bg-secondary\\/70 {\n  background-color: hsl(var(--secondary) / 0.7);\n}",
+		".dark .dark\\:text-primary {\n  color: hsl(var(--primary));\n}",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("Generate() has no\n%s\nin\n%s", want, css)
+		}
+	}
+
+	if strings.Count(css, ".p-4 {") != 1 {
+		t.Error("Generate() repeats the rule of a repeated class")
+	}
+	if strings.Contains(css, "not-a-utility") {
+		t.Error("Generate() has a rule for an unknown class")
+	}
+	if strings.Index(css, ".hover\\:underline") < strings.Index(css, ".p-4") || strings.Index(css, "@media") < strings.Index(css, ".hover\\:underline") {
+		t.Error("Generate() does not put the variants after the plain rules and the media queries last")
+	}
+}
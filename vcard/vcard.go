@@ -0,0 +1,101 @@
+// Package vcard renders personal contact details as a vCard 3.0 (RFC 2426).
+package vcard
+
+import (
+	"strings"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+const (
+	// ContentType is the MIME type of a vCard
+	ContentType = "text/vcard; charset=utf-8"
+
+	// maxLineLength is the line length after which content lines are folded
+	maxLineLength = 75
+)
+
+// valueEscaper escapes characters that have a meaning in vCard values
+var valueEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// FromPersonal renders the contact details as a vCard. A photo is only
+// included as an absolute URL; resolve a site-relative one beforehand.
+func FromPersonal(p components.PersonalInfo) []byte {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCARD")
+	writeLine(&b, "VERSION:3.0")
+
+	givenName, familyName := splitName(p.Name)
+	writeLine(&b, "N:"+escape(familyName)+";"+escape(givenName)+";;;")
+	writeLine(&b, "FN:"+escape(p.Name))
+
+	if p.Title != "" {
+		writeLine(&b, "TITLE:"+escape(p.Title))
+	}
+	if p.Email != "" {
+		writeLine(&b, "EMAIL;TYPE=INTERNET:"+escape(p.Email))
+	}
+	if p.Phone != "" {
+		writeLine(&b, "TEL;TYPE=CELL:"+escape(p.Phone))
+	}
+	if p.Location != "" {
+		locality, country, found := strings.Cut(p.Location, ", ")
+		if !found {
+			locality, country = "", p.Location
+		}
+		writeLine(&b, "ADR;TYPE=WORK:;;;"+escape(locality)+";;;"+escape(country))
+	}
+	if p.Website != "" {
+		writeLine(&b, "URL:"+escape(p.Website))
+	}
+	if p.Linkedin != "" {
+		writeLine(&b, "X-SOCIALPROFILE;TYPE=linkedin:"+escape(p.Linkedin))
+	}
+	if p.Github != "" {
+		writeLine(&b, "X-SOCIALPROFILE;TYPE=github:"+escape(p.Github))
+	}
+	if strings.Contains(p.Photo, "://") {
+		writeLine(&b, "PHOTO;VALUE=URI:"+p.Photo)
+	}
+
+	writeLine(&b, "END:VCARD")
+	return []byte(b.String())
+}
+
+// escape escapes a text value
+func escape(value string) string {
+	return valueEscaper.Replace(value)
+}
+
+// writeLine writes a CRLF-terminated content line, folding it after
+// maxLineLength bytes without splitting UTF-8 sequences
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space that counts towards the limit
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+// splitName splits a full name into given name(s) and family name
+func splitName(name string) (string, string) {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return name, ""
+	}
+	return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+}
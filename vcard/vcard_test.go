@@ -0,0 +1,79 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fairytale5571/cv_onopchenko/components"
+)
+
+func TestFromPersonal(t *testing.T) {
+	card := string(FromPersonal(components.PersonalInfo{
+		Name:     "Jane Van Doe",
+		Title:    "Developer, Backend; Go",
+		Email:    "jane@example.com",
+		Phone:    "+380000000000",
+		Location: "Kyiv, Ukraine",
+		Linkedin: "https://www.linkedin.com/in/jane",
+		Github:   "https://github.com/jane",
+		Website:  "https://jane.example.com",
+		Photo:    "https://example.com/jane.jpg",
+	}))
+
+	for _, want := range []string{
+		"BEGIN:VCARD\r\n",
+		"VERSION:3.0\r\n",
+		"N:Doe;Jane Van;;;\r\n",
+		"FN:Jane Van Doe\r\n",
+		`TITLE:Developer\, Backend\; Go` + "\r\n",
+		"EMAIL;TYPE=INTERNET:jane@example.com\r\n",
+		"TEL;TYPE=CELL:+380000000000\r\n",
+		"ADR;TYPE=WORK:;;;Kyiv;;;Ukraine\r\n",
+		"URL:https://jane.example.com\r\n",
+		"X-SOCIALPROFILE;TYPE=linkedin:https://www.linkedin.com/in/jane\r\n",
+		"X-SOCIALPROFILE;TYPE=github:https://github.com/jane\r\n",
+		"PHOTO;VALUE=URI:https://example.com/jane.jpg\r\n",
+	} {
+		if !strings.Contains(card, want) {
+			t.Errorf("vCard is missing %q:\n%s", want, card)
+		}
+	}
+	if !strings.HasSuffix(card, "END:VCARD\r\n") {
+		t.Errorf("vCard does not end with END:VCARD:\n%s", card)
+	}
+}
+
+func TestFromPersonalOmitsEmptyFields(t *testing.T) {
+	card := string(FromPersonal(components.PersonalInfo{Name: "Jane"}))
+
+	want := "BEGIN:VCARD\r\nVERSION:3.0\r\nN:;Jane;;;\r\nFN:Jane\r\nEND:VCARD\r\n"
+	if card != want {
+		t.Errorf("FromPersonal() = %q, want %q", card, want)
+	}
+}
+
+func TestFromPersonalOmitsRelativePhoto(t *testing.T) {
+	card := string(FromPersonal(components.PersonalInfo{Name: "Jane", Photo: "/static/img/jane.jpg"}))
+	if strings.Contains(card, "PHOTO") {
+		t.Errorf("vCard has a relative photo:\n%s", card)
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	var b strings.Builder
+	writeLine(&b, "NOTE:"+strings.Repeat("ї", 100))
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line is %d bytes long, want at most %d", len(line), maxLineLength)
+		}
+		if !strings.HasPrefix(line, "NOTE:") && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %q does not start with a space", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if unfolded != "NOTE:"+strings.Repeat("ї", 100)+"\r\n" {
+		t.Error("unfolding does not restore the original line")
+	}
+}